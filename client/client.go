@@ -24,6 +24,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"t73f.de/r/sx"
@@ -42,6 +43,9 @@ type Client struct {
 	tokenType string
 	expires   time.Time
 	client    http.Client
+
+	encMx sync.Mutex
+	enc   serverEncoding
 }
 
 // Base returns the base part of the URLs that are used to communicate with a Zettelstore.
@@ -183,6 +187,7 @@ func (c *Client) SetAuth(username, password string) {
 	c.token = ""
 	c.tokenType = ""
 	c.expires = time.Time{}
+	c.resetAssumedEncoding()
 }
 
 func (c *Client) executeAuthRequest(req *http.Request) error {
@@ -233,7 +238,11 @@ func (c *Client) Authenticate(ctx context.Context) error {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return c.executeAuthRequest(req)
+	if err = c.executeAuthRequest(req); err != nil {
+		return err
+	}
+	c.resetAssumedEncoding()
+	return nil
 }
 
 // RefreshToken updates the access token
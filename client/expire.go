@@ -0,0 +1,234 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/webapi"
+)
+
+// ExpireAction specifies what should be done with an expired zettel, after it
+// was optionally exported.
+type ExpireAction uint8
+
+// Values for ExpireAction
+const (
+	ExpireKeep       ExpireAction = iota // Do not change the zettel
+	ExpireDelete                         // Delete the zettel
+	ExpireVisibility                     // Change the visibility of the zettel
+)
+
+// String returns a textual representation of the action.
+func (a ExpireAction) String() string {
+	switch a {
+	case ExpireKeep:
+		return "keep"
+	case ExpireDelete:
+		return "delete"
+	case ExpireVisibility:
+		return "visibility"
+	}
+	return fmt.Sprintf("ExpireAction(%d)", a)
+}
+
+// ExpireOptions controls how expired zettel are archived.
+//
+//   - Now is the reference time. If it is zero, the current time is used.
+//   - Window extends the reference time, to also handle zettel that will
+//     expire soon.
+//   - Dir is the local directory, where expired zettel are exported to. If it
+//     is empty, zettel are not exported.
+//   - Action specifies what to do with an expired zettel after export.
+//   - Visibility is the new visibility, if Action is ExpireVisibility.
+//   - DryRun reports all steps, but does not execute them.
+type ExpireOptions struct {
+	Now        time.Time
+	Window     time.Duration
+	Dir        string
+	Action     ExpireAction
+	Visibility meta.Value
+	DryRun     bool
+}
+
+// ExpireReport is the structured result of handling expired zettel.
+//
+//   - Until is the point in time a zettel must have expired before.
+//   - Zettel lists all expired zettel, sorted by their expiration time.
+//   - ByRole and ByTag group the expired zettel by role and tag. Zettel
+//     without a role are listed under the empty string.
+type ExpireReport struct {
+	Until  time.Time
+	DryRun bool
+	Zettel []ExpiredZettel
+	ByRole webapi.Aggregate
+	ByTag  webapi.Aggregate
+}
+
+// ExpiredZettel reports on one expired zettel.
+//
+//   - File is the name of the exported file, or the empty string.
+//   - Action is the action executed after export.
+//   - Err is the first error that occurred while handling the zettel. If
+//     Err is not nil, the zettel was not changed.
+type ExpiredZettel struct {
+	Zid    id.Zid
+	Title  string
+	Role   string
+	Tags   []string
+	Expire time.Time
+	File   string
+	Action ExpireAction
+	Err    error
+}
+
+// Failed returns the number of zettel that could not be handled.
+func (r *ExpireReport) Failed() int {
+	result := 0
+	for _, ez := range r.Zettel {
+		if ez.Err != nil {
+			result++
+		}
+	}
+	return result
+}
+
+// QueryExpired returns the metadata of all zettel that expire before the
+// given time.
+func (c *Client) QueryExpired(ctx context.Context, until time.Time) ([]webapi.ZidMetaRights, error) {
+	sUntil := until.Format(id.TimestampLayout)
+	_, _, metas, err := c.QueryZettelData(ctx, meta.KeyExpire+webapi.SearchOperatorLess+sUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not rely on the server to compare timestamps of different lengths.
+	return slices.DeleteFunc(metas, func(zmr webapi.ZidMetaRights) bool {
+		return meta.ExpandTimestamp(meta.Value(zmr.Meta[meta.KeyExpire])) >= sUntil
+	}), nil
+}
+
+// Expire handles all zettel that have expired, or will expire within the
+// window given in the options. Every zettel is exported first. The action is
+// only executed, if the export was successful.
+//
+// An error is only returned, if the expired zettel could not be retrieved.
+// Errors when handling a specific zettel are stored in the report.
+func (c *Client) Expire(ctx context.Context, opts ExpireOptions) (*ExpireReport, error) {
+	if opts.Action == ExpireVisibility && opts.Visibility.AsVisibility() == meta.VisibilityUnknown {
+		return nil, fmt.Errorf("invalid visibility %q", opts.Visibility)
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	until := now.Add(opts.Window)
+	metas, err := c.QueryExpired(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Dir != "" && !opts.DryRun {
+		if err = os.MkdirAll(opts.Dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	report := NewExpireReport(until, metas)
+	report.DryRun = opts.DryRun
+	for i := range report.Zettel {
+		ez := &report.Zettel[i]
+		ez.Err = c.expireZettel(ctx, ez, opts)
+	}
+	return report, nil
+}
+
+// NewExpireReport builds a report on the given expired zettel, without
+// executing any actions.
+func NewExpireReport(until time.Time, metas []webapi.ZidMetaRights) *ExpireReport {
+	report := ExpireReport{
+		Until:  until,
+		Zettel: make([]ExpiredZettel, 0, len(metas)),
+		ByRole: webapi.Aggregate{},
+		ByTag:  webapi.Aggregate{},
+	}
+	for _, zmr := range metas {
+		expire, _ := meta.Value(zmr.Meta[meta.KeyExpire]).AsTime()
+		role := zmr.Meta[meta.KeyRole]
+		tags := meta.Value(zmr.Meta[meta.KeyTags]).AsTags()
+		report.Zettel = append(report.Zettel, ExpiredZettel{
+			Zid:    zmr.ID,
+			Title:  zmr.Meta[meta.KeyTitle],
+			Role:   role,
+			Tags:   tags,
+			Expire: expire,
+		})
+		report.ByRole[role] = append(report.ByRole[role], zmr.ID)
+		for _, tag := range tags {
+			report.ByTag[tag] = append(report.ByTag[tag], zmr.ID)
+		}
+	}
+	slices.SortStableFunc(report.Zettel, func(x, y ExpiredZettel) int {
+		if c := x.Expire.Compare(y.Expire); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Zid, y.Zid)
+	})
+	return &report
+}
+
+func (c *Client) expireZettel(ctx context.Context, ez *ExpiredZettel, opts ExpireOptions) error {
+	if opts.Dir != "" {
+		fileName := filepath.Join(opts.Dir, ez.Zid.String()+".zettel")
+		if !opts.DryRun {
+			data, err := c.GetZettel(ctx, ez.Zid, webapi.PartZettel)
+			if err != nil {
+				return err
+			}
+			if err = os.WriteFile(fileName, data, 0644); err != nil {
+				return err
+			}
+		}
+		ez.File = fileName
+	}
+
+	if opts.DryRun {
+		ez.Action = opts.Action
+		return nil
+	}
+	switch opts.Action {
+	case ExpireDelete:
+		if err := c.DeleteZettel(ctx, ez.Zid); err != nil {
+			return err
+		}
+	case ExpireVisibility:
+		zd, err := c.GetZettelData(ctx, ez.Zid)
+		if err != nil {
+			return err
+		}
+		zd.Meta[meta.KeyVisibility] = string(opts.Visibility)
+		if err = c.UpdateZettelData(ctx, ez.Zid, zd); err != nil {
+			return err
+		}
+	}
+	ez.Action = opts.Action
+	return nil
+}
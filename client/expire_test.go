@@ -0,0 +1,53 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package client_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"t73f.de/r/zsc/client"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/webapi"
+)
+
+func TestNewExpireReport(t *testing.T) {
+	t.Parallel()
+	metas := []webapi.ZidMetaRights{
+		{ID: 1, Meta: webapi.ZettelMeta{"expire": "20260301", "role": "zettel", "tags": "#a #b"}},
+		{ID: 2, Meta: webapi.ZettelMeta{"expire": "2026", "role": "zettel", "tags": "#b"}},
+		{ID: 3, Meta: webapi.ZettelMeta{"expire": "20260101120000"}},
+	}
+	report := client.NewExpireReport(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), metas)
+	var zids []id.Zid
+	for _, ez := range report.Zettel {
+		zids = append(zids, ez.Zid)
+	}
+	if exp := []id.Zid{2, 3, 1}; !slices.Equal(zids, exp) {
+		t.Errorf("order: expected %v, but got %v", exp, zids)
+	}
+	if got := report.ByRole["zettel"]; !slices.Equal(got, []id.Zid{1, 2}) {
+		t.Errorf("role zettel: expected [1 2], but got %v", got)
+	}
+	if got := report.ByRole[""]; !slices.Equal(got, []id.Zid{3}) {
+		t.Errorf("no role: expected [3], but got %v", got)
+	}
+	if got := report.ByTag["b"]; !slices.Equal(got, []id.Zid{1, 2}) {
+		t.Errorf("tag b: expected [1 2], but got %v", got)
+	}
+	if got := report.Failed(); got != 0 {
+		t.Errorf("expected no failures, but got %d", got)
+	}
+}
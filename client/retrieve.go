@@ -25,8 +25,8 @@ import (
 	"t73f.de/r/sx/sxreader"
 	"t73f.de/r/zsc/domain/id"
 	"t73f.de/r/zsc/sexp"
+	"t73f.de/r/zsc/sz"
 	"t73f.de/r/zsc/webapi"
-	"t73f.de/r/zsx"
 )
 
 var bsLF = []byte{'\n'}
@@ -80,8 +80,6 @@ func (c *Client) QueryZettelData(ctx context.Context, query string) (string, str
 		return "", "", nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
-	rdr := sxreader.MakeReader(resp.Body).SetListLimit(0) // No limit b/c number of zettel may be more than 100000. We must trust the server
-	obj, err := rdr.Read()
 	switch resp.StatusCode {
 	case http.StatusOK:
 	case http.StatusNoContent:
@@ -89,64 +87,112 @@ func (c *Client) QueryZettelData(ctx context.Context, query string) (string, str
 	default:
 		return "", "", nil, statusToError(resp)
 	}
-	if err != nil {
-		return "", "", nil, err
-	}
-	vals, err := sexp.ParseList(obj, "yppr")
-	if err != nil {
-		return "", "", nil, err
-	}
-	qVals, err := sexp.ParseList(vals[1], "ys")
-	if err != nil {
-		return "", "", nil, err
-	}
-	hVals, err := sexp.ParseList(vals[2], "ys")
-	if err != nil {
-		return "", "", nil, err
-	}
-	metaList, err := parseMetaList(vals[3].(*sx.Pair))
-	return zsx.GoValue(qVals[1]), zsx.GoValue(hVals[1]), metaList, err
-}
 
-func parseMetaList(metaPair *sx.Pair) ([]webapi.ZidMetaRights, error) {
-	var result []webapi.ZidMetaRights
-	for node := metaPair; !sx.IsNil(node); {
-		elem, isPair := sx.GetPair(node)
+	// The list of zettel may contain more than 100000 elements. Therefore it
+	// is decoded one zettel at a time. Older servers use a different encoding,
+	// which must be read completely to be converted.
+	var dec elementReader
+	if c.isV2Server(ctx) {
+		rdr := sxreader.MakeReader(resp.Body).SetListLimit(0) // No limit b/c number of zettel may be more than 100000. We must trust the server
+		obj, errRead := rdr.Read()
+		if errRead != nil {
+			return "", "", nil, errRead
+		}
+		lst, isPair := sx.GetPair(sexp.MigrateDataV2(obj))
 		if !isPair {
-			return nil, fmt.Errorf("meta-list not a proper list: %v", metaPair.String())
+			return "", "", nil, fmt.Errorf("meta-list expected, but got %T/%v", obj, obj)
 		}
-		node = elem.Tail()
-		vals, err := sexp.ParseList(elem.Car(), "yspp")
-		if err != nil {
-			return nil, err
+		dec = &listElements{lst}
+	} else {
+		dec = sexp.NewListDecoder(resp.Body)
+	}
+	if _, err = dec.Next(); err != nil { // kind
+		return "", "", nil, mandatory(err, "kind")
+	}
+	var q, h taggedString
+	if err = decodeNext(dec, &q, "query"); err != nil {
+		return "", "", nil, mandatory(err, "query")
+	}
+	if err = decodeNext(dec, &h, "human"); err != nil {
+		return "", "", nil, mandatory(err, "human")
+	}
+	var metaList []webapi.ZidMetaRights
+	for i := 0; ; i++ {
+		var z zettelMetaSx
+		if err = decodeNext(dec, &z, fmt.Sprintf("meta-list[%d]", i)); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", "", nil, err
 		}
-
-		if errSym := sexp.CheckSymbol(vals[0], sexp.SymZettel); errSym != nil {
-			return nil, errSym
+		zid, errZid := id.Parse(z.ID)
+		if errZid != nil {
+			return "", "", nil, fmt.Errorf("meta-list[%d].id: %w", i, errZid)
 		}
+		metaList = append(metaList, webapi.ZidMetaRights{
+			ID:     zid,
+			Meta:   z.Meta,
+			Rights: webapi.ZettelRights(z.Rights),
+		})
+	}
+	return q.Value, h.Value, metaList, nil
+}
 
-		zid, err := id.Parse(vals[1].(sx.String).GetValue())
-		if err != nil {
-			return nil, err
-		}
+// elementReader returns the elements of a list, one at a time. After the last
+// element, io.EOF is returned.
+type elementReader interface {
+	Next() (sx.Object, error)
+}
 
-		meta, err := sexp.ParseMeta(vals[2].(*sx.Pair))
-		if err != nil {
-			return nil, err
-		}
+// listElements returns the elements of a list that was already read.
+type listElements struct{ lst *sx.Pair }
+
+func (le *listElements) Next() (sx.Object, error) {
+	if le.lst == nil {
+		return nil, io.EOF
+	}
+	obj := le.lst.Car()
+	le.lst = le.lst.Tail()
+	return obj, nil
+}
 
-		rights, err := sexp.ParseRights(vals[3])
-		if err != nil {
-			return nil, err
+// decodeNext reads the next list element and unmarshals it into v.
+func decodeNext(dec elementReader, v any, path string) error {
+	obj, err := dec.Next()
+	if err != nil {
+		return err
+	}
+	err = sexp.Unmarshal(obj, v)
+	if pe, isPathError := err.(*sexp.PathError); isPathError {
+		if pe.Path == "" {
+			pe.Path = path
+		} else {
+			pe.Path = path + "." + pe.Path
 		}
+	}
+	return err
+}
 
-		result = append(result, webapi.ZidMetaRights{
-			ID:     zid,
-			Meta:   meta,
-			Rights: rights,
-		})
+// mandatory returns the error of reading a mandatory list element. For such
+// an element, the end of the list is unexpected.
+func mandatory(err error, path string) error {
+	if err == io.EOF {
+		return &sexp.PathError{Path: path, Err: io.ErrUnexpectedEOF}
 	}
-	return result, nil
+	return err
+}
+
+// taggedString is a list of a symbol and a string, like (query "abc").
+type taggedString struct {
+	Tag   string `sx:"tag,symbol"`
+	Value string `sx:"value"`
+}
+
+type zettelMetaSx struct {
+	_      struct{}          `sx:"zettel"`
+	ID     string            `sx:"id"`
+	Meta   webapi.ZettelMeta `sx:"meta,head"`
+	Rights sexp.Rights       `sx:"rights"`
 }
 
 // QueryAggregate returns a aggregate as a result of a query.
@@ -260,6 +306,9 @@ func (c *Client) GetZettelData(ctx context.Context, zid id.Zid) (webapi.ZettelDa
 		rdr := sxreader.MakeReader(resp.Body)
 		obj, err2 := rdr.Read()
 		if err2 == nil {
+			if c.isV2Server(ctx) {
+				obj = sexp.MigrateDataV2(obj)
+			}
 			return sexp.ParseZettel(obj)
 		}
 	}
@@ -280,6 +329,9 @@ func (c *Client) GetContentData(ctx context.Context, zid id.Zid) (content string
 		rdr := sxreader.MakeReader(resp.Body)
 		obj, err2 := rdr.Read()
 		if err2 == nil {
+			if c.isV2Server(ctx) {
+				obj = sexp.MigrateDataV2(obj)
+			}
 			return sexp.ParseContent(obj)
 		}
 	}
@@ -372,7 +424,13 @@ func (c *Client) getSz(ctx context.Context, zid id.Zid, part string, parseOnly b
 	if resp.StatusCode != http.StatusOK {
 		return nil, statusToError(resp)
 	}
-	return sxreader.MakeReader(bufio.NewReaderSize(resp.Body, 8)).Read()
+	obj, err := sxreader.MakeReader(bufio.NewReaderSize(resp.Body, 8)).Read()
+	if err == nil && c.isV2Server(ctx) {
+		if node, isPair := sx.GetPair(obj); isPair {
+			obj = sz.MigrateV2(node)
+		}
+	}
+	return obj, err
 }
 
 // GetMetaData returns the metadata of a zettel.
@@ -393,6 +451,9 @@ func (c *Client) GetMetaData(ctx context.Context, zid id.Zid) (webapi.MetaRights
 	if err != nil {
 		return webapi.MetaRights{}, err
 	}
+	if c.isV2Server(ctx) {
+		obj = sexp.MigrateDataV2(obj)
+	}
 	vals, err := sexp.ParseList(obj, "ypp")
 	if err != nil {
 		return webapi.MetaRights{}, err
@@ -431,19 +492,14 @@ func (c *Client) GetReferences(ctx context.Context, zid id.Zid, part string) (ur
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
-	rdr := sxreader.MakeReader(resp.Body)
-	obj, err := rdr.Read()
 	if resp.StatusCode != http.StatusOK {
 		return nil, statusToError(resp)
 	}
-	if err != nil {
-		return nil, err
-	}
-	seq, isSeq := sx.GetSequence(obj)
-	if !isSeq {
-		return nil, fmt.Errorf("not a sequence: %T/%v", obj, obj)
-	}
-	for val := range seq.Values() {
+	dec := sexp.NewListDecoder(resp.Body)
+	for val, errVal := range dec.All() {
+		if errVal != nil {
+			return nil, errVal
+		}
 		if s, isString := sx.GetString(val); isString {
 			urls = append(urls, s.GetValue())
 		}
@@ -463,18 +519,69 @@ func (c *Client) GetVersionInfo(ctx context.Context) (VersionInfo, error) {
 	}
 	rdr := sxreader.MakeReader(resp.Body)
 	obj, err := rdr.Read()
-	if err == nil {
-		if vals, errVals := sexp.ParseList(obj, "iiiss"); errVals == nil {
-			return VersionInfo{
-				Major: int(vals[0].(sx.Int64)),
-				Minor: int(vals[1].(sx.Int64)),
-				Patch: int(vals[2].(sx.Int64)),
-				Info:  vals[3].(sx.String).GetValue(),
-				Hash:  vals[4].(sx.String).GetValue(),
-			}, nil
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	vals, err := sexp.ParseList(obj, "iiiss")
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	return VersionInfo{
+		Major: int(vals[0].(sx.Int64)),
+		Minor: int(vals[1].(sx.Int64)),
+		Patch: int(vals[2].(sx.Int64)),
+		Info:  vals[3].(sx.String).GetValue(),
+		Hash:  vals[4].(sx.String).GetValue(),
+	}, nil
+}
+
+// firstV3Major is the first major version of Zettelstore that uses the sz and
+// data encodings of version 3.
+const firstV3Major = 3
+
+// serverEncoding specifies the sz and data encodings used by the Zettelstore.
+type serverEncoding uint8
+
+const (
+	encodingUnknown   serverEncoding = iota // Version not yet retrieved
+	encodingV2                              // Server uses encodings of version 2
+	encodingV3                              // Server uses encodings of version 3
+	encodingV3Assumed                       // Retrieval failed, retried after authentication
+)
+
+// isV2Server returns true, if the Zettelstore uses the sz and data encodings
+// of version 2. Its version is retrieved once. If this fails, e.g. because
+// the user is not yet authenticated, or if the major version is unknown,
+// version 3 is assumed until the next authentication.
+func (c *Client) isV2Server(ctx context.Context) bool {
+	c.encMx.Lock()
+	enc := c.enc
+	c.encMx.Unlock()
+	if enc == encodingUnknown {
+		// The mutex is not held while retrieving the version. Concurrent
+		// calls may retrieve it more than once, with the same result.
+		enc = encodingV3
+		if vi, err := c.GetVersionInfo(ctx); err != nil || vi.Major == 0 {
+			// Major version 0 means that the version is not known.
+			enc = encodingV3Assumed
+		} else if vi.Major < firstV3Major {
+			enc = encodingV2
 		}
+		c.encMx.Lock()
+		c.enc = enc
+		c.encMx.Unlock()
+	}
+	return enc == encodingV2
+}
+
+// resetAssumedEncoding allows to retrieve the version again, if it failed
+// before.
+func (c *Client) resetAssumedEncoding() {
+	c.encMx.Lock()
+	if c.enc == encodingV3Assumed {
+		c.enc = encodingUnknown
 	}
-	return VersionInfo{}, err
+	c.encMx.Unlock()
 }
 
 // VersionInfo contains version information of the associated Zettelstore.
@@ -0,0 +1,101 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+
+	"t73f.de/r/zsc/client"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/webapi"
+)
+
+const szV2 = `(BLOCK (HEADING 1 () "slug" "frag" (TEXT "h")))`
+
+// newVersionServer returns a test server that reports the given version and
+// that returns a heading in sz encoding of version 2.
+func newVersionServer(t *testing.T, version string) *client.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/x":
+			_, _ = w.Write([]byte(version))
+		case "/z/" + id.ZidDefaultHome.String():
+			_, _ = w.Write([]byte(szV2))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.NewClient(u)
+}
+
+func TestGetVersionInfo(t *testing.T) {
+	t.Parallel()
+	c := newVersionServer(t, `(2 16 0 "" "abc")`)
+	vi, err := c.GetVersionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (client.VersionInfo{Major: 2, Minor: 16, Hash: "abc"}); vi != exp {
+		t.Errorf("expected %v, but got %v", exp, vi)
+	}
+
+	c = newVersionServer(t, `(2 16)`)
+	if vi, err = c.GetVersionInfo(context.Background()); err == nil {
+		t.Errorf("error expected, but got %v", vi)
+	}
+}
+
+func TestMigrateV2Server(t *testing.T) {
+	t.Parallel()
+	obj, err := sxreader.MakeReader(strings.NewReader(szV2)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := sx.GetPair(obj)
+	v3 := sz.MigrateV2(node).String()
+	testcases := []struct {
+		version string
+		exp     string
+	}{
+		{`(2 16 0 "" "")`, v3},
+		{`(3 0 0 "" "")`, szV2},
+		{`(0 0 0 "dev" "")`, szV2},
+		{`(invalid)`, szV2},
+	}
+	for i, tc := range testcases {
+		c := newVersionServer(t, tc.version)
+		got, err := c.GetEvaluatedSz(context.Background(), id.ZidDefaultHome, webapi.PartContent)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if got.String() != tc.exp {
+			t.Errorf("%d: version %v: expected %v, but got %v", i, tc.version, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package cred provides functions to create and check the credentials of
+// user zettel, compatible with Zettelstore.
+//
+// Zettelstore stores the bcrypt hash of the zettel identifier of the user
+// zettel, the user identification, and the password.
+package cred
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+// ErrMismatch is returned, if a password does not match a credential.
+var ErrMismatch = errors.New("credential does not match")
+
+// HashCredential returns the hashed credential of the given password for
+// the user with the given zettel identifier and user identification.
+func HashCredential(zid id.Zid, ident, password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword(fullCredential(zid, ident, password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// CompareHashAndCredential returns true, if the hashed credential was
+// created from the given zettel identifier, user identification, and
+// password.
+func CompareHashAndCredential(hashed string, zid id.Zid, ident, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), fullCredential(zid, ident, password))
+	if err == nil {
+		return true, nil
+	}
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return false, err
+}
+
+func fullCredential(zid id.Zid, ident, password string) []byte {
+	result := make([]byte, 0, id.LengthZid+len(ident)+len(password)+2)
+	result = append(result, zid.Bytes()...)
+	result = append(result, ' ')
+	result = append(result, ident...)
+	result = append(result, ' ')
+	return append(result, password...)
+}
+
+// NewUser returns the metadata of a user zettel with the given identifier,
+// user identification, role, and password.
+func NewUser(zid id.Zid, ident string, role meta.UserRole, password string) (*meta.Meta, error) {
+	if !isValidIdent(ident) {
+		return nil, fmt.Errorf("invalid user identification %q", ident)
+	}
+	m := meta.New(zid)
+	m.Set(meta.KeyTitle, meta.Value(ident))
+	m.Set(meta.KeyRole, meta.ValueRoleConfiguration)
+	m.Set(meta.KeySyntax, meta.ValueSyntaxNone)
+	m.Set(meta.KeyUserID, meta.Value(ident))
+	if role != meta.UserRoleUnknown {
+		userRole, found := userRoleValues[role]
+		if !found {
+			return nil, fmt.Errorf("invalid user role %v", role)
+		}
+		m.Set(meta.KeyUserRole, userRole)
+	}
+	if err := SetPassword(m, password); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isValidIdent returns true, if the user identification is a single word in
+// lower case. Otherwise, it would be changed when the metadata is parsed.
+func isValidIdent(ident string) bool {
+	return ident != "" && !strings.ContainsFunc(ident, unicode.IsSpace) && ident == strings.ToLower(ident)
+}
+
+var userRoleValues = map[meta.UserRole]meta.Value{
+	meta.UserRoleCreator: meta.ValueUserRoleCreator,
+	meta.UserRoleReader:  meta.ValueUserRoleReader,
+	meta.UserRoleWriter:  meta.ValueUserRoleWriter,
+	meta.UserRoleOwner:   meta.ValueUserRoleOwner,
+}
+
+// SetPassword stores the credential of the given password in the metadata of
+// a user zettel.
+func SetPassword(m *meta.Meta, password string) error {
+	ident, found := m.Get(meta.KeyUserID)
+	if !found || ident == "" {
+		return fmt.Errorf("zettel %v is not a user zettel", m.Zid)
+	}
+	hashed, err := HashCredential(m.Zid, string(ident), password)
+	if err != nil {
+		return err
+	}
+	m.Set(meta.KeyCredential, meta.Value(hashed))
+	return nil
+}
+
+// CheckPassword returns true, if the password matches the credential stored
+// in the metadata of a user zettel.
+func CheckPassword(m *meta.Meta, password string) (bool, error) {
+	ident, found := m.Get(meta.KeyUserID)
+	if !found || ident == "" {
+		return false, fmt.Errorf("zettel %v is not a user zettel", m.Zid)
+	}
+	hashed, found := m.Get(meta.KeyCredential)
+	if !found || hashed == "" {
+		return false, nil
+	}
+	return CompareHashAndCredential(string(hashed), m.Zid, string(ident), password)
+}
+
+// ChangePassword replaces the credential of a user zettel, if the old
+// password matches the current credential. Otherwise, ErrMismatch is
+// returned and the metadata is not changed.
+func ChangePassword(m *meta.Meta, oldPassword, newPassword string) error {
+	ok, err := CheckPassword(m, oldPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMismatch
+	}
+	return SetPassword(m, newPassword)
+}
+
+// ChangeIdent changes the user identification of a user zettel. Since the
+// credential depends on the identification, the password must be given to
+// compute the new credential.
+func ChangeIdent(m *meta.Meta, newIdent, password string) error {
+	if !isValidIdent(newIdent) {
+		return fmt.Errorf("invalid user identification %q", newIdent)
+	}
+	ok, err := CheckPassword(m, password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMismatch
+	}
+	hashed, err := HashCredential(m.Zid, newIdent, password)
+	if err != nil {
+		return err
+	}
+	m.Set(meta.KeyUserID, meta.Value(newIdent))
+	m.Set(meta.KeyCredential, meta.Value(hashed))
+	return nil
+}
@@ -0,0 +1,105 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package cred_test
+
+import (
+	"testing"
+
+	"t73f.de/r/zsc/domain/cred"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+func TestCompareCredential(t *testing.T) {
+	t.Parallel()
+	// Hash of "00010000000001 owner secret", with a cost of 5.
+	const hashed = "$2b$05$XXXXXXXXXXXXXXXXXXXXXuiY.cjYpJQmC9Xu27jvYAfjVhuIfhKcS"
+	testCases := []struct {
+		zid      id.Zid
+		ident    string
+		password string
+		exp      bool
+	}{
+		{10000000001, "owner", "secret", true},
+		{10000000001, "owner", "Secret", false},
+		{10000000001, "owner2", "secret", false},
+		{1, "owner", "secret", false},
+	}
+	for i, tc := range testCases {
+		got, err := cred.CompareHashAndCredential(hashed, tc.zid, tc.ident, tc.password)
+		if err != nil {
+			t.Errorf("%d: unexpected error %v", i, err)
+		} else if got != tc.exp {
+			t.Errorf("%d: %v/%q/%q expected %v, but got %v", i, tc.zid, tc.ident, tc.password, tc.exp, got)
+		}
+	}
+}
+
+func TestUser(t *testing.T) {
+	t.Parallel()
+	if _, err := cred.NewUser(3, "Bob Smith", meta.UserRoleWriter, "secret"); err == nil {
+		t.Error("invalid user identification must result in an error")
+	}
+
+	m, err := cred.NewUser(3, "bob", meta.UserRoleWriter, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, exp := range map[string]meta.Value{
+		meta.KeyUserID:   "bob",
+		meta.KeyUserRole: meta.ValueUserRoleWriter,
+		meta.KeyRole:     meta.ValueRoleConfiguration,
+	} {
+		if got := m.GetDefault(key, ""); got != exp {
+			t.Errorf("key %q: expected %q, but got %q", key, exp, got)
+		}
+	}
+	if ok, _ := cred.CheckPassword(m, "secret"); !ok {
+		t.Error("password must match")
+	}
+	if ok, _ := cred.CheckPassword(m, "wrong"); ok {
+		t.Error("wrong password must not match")
+	}
+
+	oldCred := m.GetDefault(meta.KeyCredential, "")
+	if err = cred.ChangePassword(m, "wrong", "new"); err != cred.ErrMismatch {
+		t.Errorf("expected ErrMismatch, but got %v", err)
+	}
+	if got := m.GetDefault(meta.KeyCredential, ""); got != oldCred {
+		t.Error("credential must not change on mismatch")
+	}
+	if err = cred.ChangePassword(m, "secret", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := cred.CheckPassword(m, "new"); !ok {
+		t.Error("new password must match")
+	}
+
+	if err = cred.ChangeIdent(m, "robert", "secret"); err != cred.ErrMismatch {
+		t.Errorf("expected ErrMismatch, but got %v", err)
+	}
+	if err = cred.ChangeIdent(m, "robert", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetDefault(meta.KeyUserID, ""); got != "robert" {
+		t.Errorf("expected user-id %q, but got %q", "robert", got)
+	}
+	if ok, _ := cred.CheckPassword(m, "new"); !ok {
+		t.Error("password must match after changing the identification")
+	}
+
+	if _, err = cred.CheckPassword(meta.New(4), "secret"); err == nil {
+		t.Error("zettel without user-id must result in an error")
+	}
+}
@@ -0,0 +1,229 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package id
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AllocatorConfig contains the configuration of an [Allocator].
+type AllocatorConfig struct {
+	// Location is the time zone of the zettel identifier. If it is nil,
+	// the local time zone is used, like [New] does.
+	Location *time.Location
+
+	// Used contains all zettel identifier that must not be allocated, e.g.
+	// a [t73f.de/r/zsc/domain/id/idset.Set]. May be nil.
+	Used interface{ Contains(Zid) bool }
+
+	// StateFile is the name of a file that stores the last allocated
+	// identifier. It allows several processes on one host to share the
+	// allocation. If it is empty, the state is kept in memory only.
+	StateFile string
+
+	// Now returns the current time. If it is nil, [time.Now] is used.
+	Now func() time.Time
+}
+
+// Allocator hands out unique zettel identifier, which are strictly
+// increasing. It is safe for concurrent use.
+//
+// If more identifier are needed than seconds have passed, the identifier
+// will refer to a point in time in the near future. The same is true, if the
+// wall clock of the time zone goes backwards, e.g. at the end of daylight
+// saving time.
+type Allocator struct {
+	mx   sync.Mutex
+	cfg  AllocatorConfig
+	last Zid
+}
+
+// NewAllocator creates a new allocator.
+func NewAllocator(cfg AllocatorConfig) *Allocator {
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Allocator{cfg: cfg}
+}
+
+// Lock file handling for a shared state file.
+const (
+	lockRetryDelay = 10 * time.Millisecond
+	lockTimeout    = 5 * time.Second
+	lockStale      = 30 * time.Second
+)
+
+// ErrLockTimeout is returned, if the state file of an allocator could not be
+// locked in time.
+var ErrLockTimeout = errors.New("id: timeout while locking state file")
+
+// Next returns the next unique zettel identifier.
+func (a *Allocator) Next() (Zid, error) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if a.cfg.StateFile == "" {
+		zid, err := a.next(a.last)
+		if err != nil {
+			return Invalid, err
+		}
+		a.last = zid
+		return zid, nil
+	}
+
+	unlock, err := lockFile(a.cfg.StateFile + ".lock")
+	if err != nil {
+		return Invalid, err
+	}
+	defer unlock()
+	last, err := a.readState()
+	if err != nil {
+		return Invalid, err
+	}
+	if a.last > last {
+		last = a.last
+	}
+	zid, err := a.next(last)
+	if err != nil {
+		return Invalid, err
+	}
+	if err = a.writeState(zid); err != nil {
+		return Invalid, err
+	}
+	a.last = zid
+	return zid, nil
+}
+
+// next returns the first unused identifier after last. Identifier are
+// compared instead of points in time, because an identifier is the wall clock
+// time, which is not monotonic.
+func (a *Allocator) next(last Zid) (Zid, error) {
+	t := a.cfg.Now().In(a.cfg.Location)
+	zid := FromTime(t)
+	if zid == Invalid {
+		return Invalid, fmt.Errorf("id: no valid identifier for %v", t)
+	}
+	var err error
+	if zid <= last {
+		if zid, err = successor(last); err != nil {
+			return Invalid, err
+		}
+	}
+	for a.cfg.Used != nil && a.cfg.Used.Contains(zid) {
+		if zid, err = successor(zid); err != nil {
+			return Invalid, err
+		}
+	}
+	return zid, nil
+}
+
+// successor returns the identifier of the next second on the wall clock. The
+// time is calculated in UTC, which has no gaps or repeated hours.
+func successor(zid Zid) (Zid, error) {
+	t, err := time.Parse(TimestampLayout, zid.String())
+	if err != nil {
+		return Invalid, fmt.Errorf("id: %v is not a timestamp", zid)
+	}
+	next := FromTime(t.Add(time.Second))
+	if next == Invalid {
+		return Invalid, fmt.Errorf("id: no valid identifier after %v", zid)
+	}
+	return next, nil
+}
+
+func (a *Allocator) readState() (Zid, error) {
+	data, err := os.ReadFile(a.cfg.StateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Invalid, nil
+		}
+		return Invalid, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Invalid, nil
+	}
+	zid, err := Parse(string(data))
+	if err == nil && !zid.IsTimestamp() {
+		err = strconv.ErrSyntax
+	}
+	if err != nil {
+		return Invalid, fmt.Errorf("id: invalid state file %q: %w", a.cfg.StateFile, err)
+	}
+	return zid, nil
+}
+
+func (a *Allocator) writeState(zid Zid) error {
+	tmpName := a.cfg.StateFile + ".tmp"
+	if err := os.WriteFile(tmpName, append(zid.Bytes(), '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, a.cfg.StateFile)
+}
+
+// lockFile creates the given lock file exclusively and returns a function to
+// remove it. A lock file that is older than lockStale is assumed to be left
+// over by a crashed process. It is taken over by [removeStaleLock].
+func lockFile(name string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(name) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if isStale(name) {
+			removeStaleLock(name)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+		time.Sleep(lockRetryDelay)
+	}
+}
+
+// isStale returns true, if the given lock file is older than lockStale.
+func isStale(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && time.Since(fi.ModTime()) > lockStale
+}
+
+// removeStaleLock removes a stale lock file. Several processes may detect the
+// stale lock at the same time. Therefore, the lock file is first renamed to a
+// unique name, which succeeds for only one of them. If the renamed file is not
+// stale, another process has just created it, and it is restored.
+func removeStaleLock(name string) {
+	staleName := fmt.Sprintf("%s.%d.%d", name, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(name, staleName); err != nil {
+		return
+	}
+	if !isStale(staleName) {
+		// Link fails, if yet another process has created a new lock file.
+		_ = os.Link(staleName, name)
+	}
+	_ = os.Remove(staleName)
+}
@@ -0,0 +1,181 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package id_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+)
+
+func fixedNow() time.Time { return time.Date(2026, 10, 18, 12, 59, 58, 500, time.UTC) }
+
+func TestAllocator(t *testing.T) {
+	t.Parallel()
+	alloc := id.NewAllocator(id.AllocatorConfig{
+		Location: time.UTC,
+		Used:     idset.New(id.MustParse("20261018130000")),
+		Now:      fixedNow,
+	})
+	exp := []string{"20261018125958", "20261018125959", "20261018130001", "20261018130002"}
+	for i, s := range exp {
+		zid, err := alloc.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := zid.String(); got != s {
+			t.Errorf("%d: expected %q, but got %q", i, s, got)
+		}
+	}
+}
+
+func TestAllocatorConcurrent(t *testing.T) {
+	t.Parallel()
+	stateFile := filepath.Join(t.TempDir(), "zid")
+	const workers, count = 4, 25
+	var wg sync.WaitGroup
+	var mx sync.Mutex
+	zids := idset.New()
+	for range workers {
+		// Each worker simulates its own process, sharing the state file.
+		alloc := id.NewAllocator(id.AllocatorConfig{Location: time.UTC, StateFile: stateFile, Now: fixedNow})
+		wg.Go(func() {
+			for range count {
+				zid, err := alloc.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mx.Lock()
+				zids.Add(zid)
+				mx.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if got := zids.Length(); got != workers*count {
+		t.Errorf("expected %d unique identifier, but got %d", workers*count, got)
+	}
+
+	alloc := id.NewAllocator(id.AllocatorConfig{Location: time.UTC, StateFile: stateFile, Now: fixedNow})
+	zid, err := alloc.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := id.MustParse("20261018130138"); zid != exp {
+		t.Errorf("expected %v after reading state, but got %v", exp, zid)
+	}
+}
+
+func TestAllocatorDST(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	// At 01:00 UTC, the wall clock in Berlin goes back from 03:00 to 02:00.
+	instants := []time.Time{
+		time.Date(2026, 10, 25, 0, 59, 59, 0, time.UTC),
+		time.Date(2026, 10, 25, 1, 0, 1, 0, time.UTC),
+		time.Date(2026, 10, 25, 1, 0, 2, 0, time.UTC),
+	}
+	var mx sync.Mutex
+	now := func() time.Time {
+		mx.Lock()
+		defer mx.Unlock()
+		result := instants[0]
+		if len(instants) > 1 {
+			instants = instants[1:]
+		}
+		return result
+	}
+	stateFile := filepath.Join(t.TempDir(), "zid")
+	exp := []string{"20261025025959", "20261025030000", "20261025030001"}
+	alloc := id.NewAllocator(id.AllocatorConfig{Location: loc, StateFile: stateFile, Now: now})
+	for i, s := range exp {
+		zid, errNext := alloc.Next()
+		if errNext != nil {
+			t.Fatal(errNext)
+		}
+		if got := zid.String(); got != s {
+			t.Errorf("%d: expected %q, but got %q", i, s, got)
+		}
+	}
+
+	// A new allocator continues after the state, although the clock went back.
+	alloc = id.NewAllocator(id.AllocatorConfig{Location: loc, StateFile: stateFile, Now: now})
+	zid, err := alloc.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "20261025030002"; zid.String() != exp {
+		t.Errorf("expected %q after reading state, but got %q", exp, zid)
+	}
+}
+
+func TestAllocatorInvalidTime(t *testing.T) {
+	t.Parallel()
+	alloc := id.NewAllocator(id.AllocatorConfig{
+		Location: time.UTC,
+		Now:      func() time.Time { return time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC) },
+	})
+	if zid, err := alloc.Next(); err == nil {
+		t.Errorf("error expected, but got %v", zid)
+	}
+
+	alloc = id.NewAllocator(id.AllocatorConfig{
+		Location: time.UTC,
+		Now:      func() time.Time { return time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC) },
+	})
+	if _, err := alloc.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if zid, err := alloc.Next(); err == nil {
+		t.Errorf("error expected after last identifier, but got %v", zid)
+	}
+}
+
+func TestAllocatorStaleLock(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "zid")
+	lockName := stateFile + ".lock"
+	if err := os.WriteFile(lockName, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(lockName, old, old); err != nil {
+		t.Fatal(err)
+	}
+	alloc := id.NewAllocator(id.AllocatorConfig{Location: time.UTC, StateFile: stateFile, Now: fixedNow})
+	if _, err := alloc.Next(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "zid" {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("only state file expected, but got %v", names)
+	}
+}
@@ -0,0 +1,599 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset
+
+import (
+	"math/bits"
+	"slices"
+	"strings"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+// Bitmap is a set of zettel identifier, stored as a compressed bitmap.
+//
+// The identifier are partitioned by their upper bits. The lower 16 bits of
+// all identifier of one partition are stored in a container, which is either
+// a sorted array (for few elements) or a bitmap (for many elements), similar
+// to "Roaring Bitmaps". For a large number of elements, adding, removing, and
+// building the union is much faster than with [Set], and less memory is used.
+//
+// Bitmap provides the same methods as [Set]. A nil *Bitmap is an empty set.
+type Bitmap struct {
+	keys  []uint32     // sorted upper bits of the identifier
+	conts []*container // containers, corresponding to keys
+}
+
+// NewBitmap returns a new bitmap set with the given initial values.
+func NewBitmap(zids ...id.Zid) *Bitmap {
+	var result Bitmap
+	for _, zid := range zids {
+		result.add(zid)
+	}
+	return &result
+}
+
+// Bitmap returns the set as a bitmap set.
+func (s *Set) Bitmap() *Bitmap {
+	if s == nil {
+		return nil
+	}
+	return NewBitmap(s.seq...)
+}
+
+// Set returns the bitmap set as a sorted set.
+func (b *Bitmap) Set() *Set {
+	if b == nil {
+		return nil
+	}
+	return &Set{seq: b.SafeSorted()}
+}
+
+// String returns a string representation of the set.
+func (b *Bitmap) String() string {
+	return "{" + b.MetaString() + "}"
+}
+
+// MetaString returns a string representation of the set to be stored as metadata.
+func (b *Bitmap) MetaString() string {
+	var sb strings.Builder
+	b.ForEach(func(zid id.Zid) {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.Write(zid.Bytes())
+	})
+	return sb.String()
+}
+
+// MetaValue returns a metadata value representation of the set.
+func (b *Bitmap) MetaValue() meta.Value { return meta.Value(b.MetaString()) }
+
+// IsEmpty returns true, if the set conains no element.
+func (b *Bitmap) IsEmpty() bool { return b == nil || len(b.keys) == 0 }
+
+// Length returns the number of elements in this set.
+func (b *Bitmap) Length() int {
+	if b == nil {
+		return 0
+	}
+	result := 0
+	for _, c := range b.conts {
+		result += c.card
+	}
+	return result
+}
+
+// Clone returns a copy of the given set.
+func (b *Bitmap) Clone() *Bitmap {
+	if b == nil {
+		return nil
+	}
+	conts := make([]*container, len(b.conts))
+	for i, c := range b.conts {
+		conts[i] = c.clone()
+	}
+	return &Bitmap{keys: slices.Clone(b.keys), conts: conts}
+}
+
+// Add adds a zettel identifier to the set.
+func (b *Bitmap) Add(zid id.Zid) *Bitmap {
+	if b == nil {
+		return NewBitmap(zid)
+	}
+	b.add(zid)
+	return b
+}
+
+// AddSlice adds all identifier of the given slice to the set.
+func (b *Bitmap) AddSlice(sl []id.Zid) *Bitmap {
+	if b == nil {
+		return NewBitmap(sl...)
+	}
+	for _, zid := range sl {
+		b.add(zid)
+	}
+	return b
+}
+
+// Contains return true if the set is non-nil and the set contains the given Zettel identifier.
+func (b *Bitmap) Contains(zid id.Zid) bool { return b != nil && b.contains(zid) }
+
+// ContainsOrNil return true if the set is nil or if the set contains the given Zettel identifier.
+func (b *Bitmap) ContainsOrNil(zid id.Zid) bool { return b == nil || b.contains(zid) }
+
+// SafeSorted returns the set as a new sorted slice of zettel identifier.
+func (b *Bitmap) SafeSorted() []id.Zid {
+	if b == nil {
+		return nil
+	}
+	result := make([]id.Zid, 0, b.Length())
+	b.ForEach(func(zid id.Zid) { result = append(result, zid) })
+	return result
+}
+
+// IntersectOrSet removes all zettel identifier that are not in the other set.
+// Both sets can be modified by this method. One of them is the set returned.
+// It contains the intersection of both, if b is not nil.
+//
+// If b == nil, then the other set is always returned.
+func (b *Bitmap) IntersectOrSet(other *Bitmap) *Bitmap {
+	if b == nil {
+		return other.Clone()
+	}
+	if other == nil {
+		clear(b.conts)
+		b.keys, b.conts = b.keys[:0], b.conts[:0]
+		return b
+	}
+	topos, bpos, opos := 0, 0, 0
+	for bpos < len(b.keys) && opos < len(other.keys) {
+		bk, ok := b.keys[bpos], other.keys[opos]
+		if bk < ok {
+			bpos++
+			continue
+		}
+		if bk > ok {
+			opos++
+			continue
+		}
+		if c := b.conts[bpos]; c.intersect(other.conts[opos]) {
+			b.keys[topos], b.conts[topos] = bk, c
+			topos++
+		}
+		bpos++
+		opos++
+	}
+	clear(b.conts[topos:])
+	b.keys, b.conts = b.keys[:topos], b.conts[:topos]
+	return b
+}
+
+// IUnion adds the elements of set other to b.
+func (b *Bitmap) IUnion(other *Bitmap) *Bitmap {
+	if other.IsEmpty() {
+		return b
+	}
+	if b == nil {
+		return other.Clone()
+	}
+	keys := make([]uint32, 0, len(b.keys)+len(other.keys))
+	conts := make([]*container, 0, cap(keys))
+	bpos, opos := 0, 0
+	for bpos < len(b.keys) && opos < len(other.keys) {
+		bk, ok := b.keys[bpos], other.keys[opos]
+		switch {
+		case bk < ok:
+			keys, conts = append(keys, bk), append(conts, b.conts[bpos])
+			bpos++
+		case bk > ok:
+			keys, conts = append(keys, ok), append(conts, other.conts[opos].clone())
+			opos++
+		default:
+			c := b.conts[bpos]
+			c.union(other.conts[opos])
+			keys, conts = append(keys, bk), append(conts, c)
+			bpos++
+			opos++
+		}
+	}
+	keys, conts = append(keys, b.keys[bpos:]...), append(conts, b.conts[bpos:]...)
+	for ; opos < len(other.keys); opos++ {
+		keys, conts = append(keys, other.keys[opos]), append(conts, other.conts[opos].clone())
+	}
+	b.keys, b.conts = keys, conts
+	return b
+}
+
+// ISubstract removes all zettel identifier from 'b' that are in the set 'other'.
+func (b *Bitmap) ISubstract(other *Bitmap) {
+	if b.IsEmpty() || other.IsEmpty() {
+		return
+	}
+	topos, opos := 0, 0
+	for bpos, bk := range b.keys {
+		for opos < len(other.keys) && other.keys[opos] < bk {
+			opos++
+		}
+		c := b.conts[bpos]
+		if opos < len(other.keys) && other.keys[opos] == bk && !c.andNot(other.conts[opos]) {
+			continue
+		}
+		b.keys[topos], b.conts[topos] = bk, c
+		topos++
+	}
+	clear(b.conts[topos:])
+	b.keys, b.conts = b.keys[:topos], b.conts[:topos]
+}
+
+// Diff returns the difference sets between the two sets: the first difference
+// set is the set of elements that are in other, but not in b; the second
+// difference set is the set of element that are in b but not in other.
+//
+// See [Set.Diff] for details.
+func (b *Bitmap) Diff(other *Bitmap) (newS, remS *Bitmap) {
+	if b.IsEmpty() {
+		return other.Clone(), nil
+	}
+	if other.IsEmpty() {
+		return nil, b.Clone()
+	}
+	newS, remS = other.Clone(), b.Clone()
+	newS.ISubstract(b)
+	remS.ISubstract(other)
+	if newS.IsEmpty() {
+		newS = nil
+	}
+	if remS.IsEmpty() {
+		remS = nil
+	}
+	return newS, remS
+}
+
+// Remove the identifier from the set.
+func (b *Bitmap) Remove(zid id.Zid) *Bitmap {
+	if b.IsEmpty() {
+		return nil
+	}
+	key, low := splitZid(zid)
+	if pos, found := slices.BinarySearch(b.keys, key); found && b.conts[pos].remove(low) && b.conts[pos].card == 0 {
+		b.keys = slices.Delete(b.keys, pos, pos+1)
+		b.conts = slices.Delete(b.conts, pos, pos+1)
+	}
+	if len(b.keys) == 0 {
+		return nil
+	}
+	return b
+}
+
+// Equal returns true if the other set is equal to the given set.
+func (b *Bitmap) Equal(other *Bitmap) bool {
+	if b == nil {
+		return other == nil
+	}
+	if other == nil || !slices.Equal(b.keys, other.keys) {
+		return false
+	}
+	for i, c := range b.conts {
+		if !c.equal(other.conts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForEach calls the given function for each element of the set.
+//
+// Every element is bigger than the previous one.
+func (b *Bitmap) ForEach(fn func(zid id.Zid)) {
+	if b != nil {
+		for i, key := range b.keys {
+			high := id.Zid(key) << 16
+			b.conts[i].forEach(func(low uint16) { fn(high | id.Zid(low)) })
+		}
+	}
+}
+
+// Pop return one arbitrary element of the set.
+func (b *Bitmap) Pop() (id.Zid, bool) {
+	if b.IsEmpty() {
+		return id.Invalid, false
+	}
+	last := len(b.keys) - 1
+	zid := id.Zid(b.keys[last])<<16 | id.Zid(b.conts[last].max())
+	b.Remove(zid)
+	return zid, true
+}
+
+// Optimize the amount of memory to store the set.
+func (b *Bitmap) Optimize() {
+	if b != nil {
+		b.keys, b.conts = slices.Clip(b.keys), slices.Clip(b.conts)
+		for _, c := range b.conts {
+			c.arr = slices.Clip(c.arr)
+		}
+	}
+}
+
+// ----- unchecked base operations
+
+func splitZid(zid id.Zid) (uint32, uint16) { return uint32(zid >> 16), uint16(zid) }
+
+func (b *Bitmap) add(zid id.Zid) {
+	key, low := splitZid(zid)
+	pos, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		b.keys = slices.Insert(b.keys, pos, key)
+		b.conts = slices.Insert(b.conts, pos, &container{})
+	}
+	b.conts[pos].add(low)
+}
+
+func (b *Bitmap) contains(zid id.Zid) bool {
+	key, low := splitZid(zid)
+	pos, found := slices.BinarySearch(b.keys, key)
+	return found && b.conts[pos].contains(low)
+}
+
+// ----- container of the lower 16 bits
+
+// maxArrayCard is the maximum number of elements stored in an array
+// container. Above this number, a bitmap needs less memory.
+const maxArrayCard = 4096
+
+const bitmapWords = 1 << 16 / 64
+
+// container stores a set of 16 bit values. If bits is nil, the values are
+// stored in the sorted slice arr. Otherwise bits is used, and arr is nil.
+type container struct {
+	arr  []uint16
+	bits *[bitmapWords]uint64
+	card int
+}
+
+func (c *container) clone() *container {
+	result := container{card: c.card}
+	if c.bits != nil {
+		words := *c.bits
+		result.bits = &words
+	} else {
+		result.arr = slices.Clone(c.arr)
+	}
+	return &result
+}
+
+func (c *container) contains(v uint16) bool {
+	if c.bits != nil {
+		return c.bits[v>>6]&(1<<(v&63)) != 0
+	}
+	_, found := slices.BinarySearch(c.arr, v)
+	return found
+}
+
+func (c *container) add(v uint16) bool {
+	if c.bits != nil {
+		w, mask := v>>6, uint64(1)<<(v&63)
+		if c.bits[w]&mask != 0 {
+			return false
+		}
+		c.bits[w] |= mask
+		c.card++
+		return true
+	}
+	pos, found := slices.BinarySearch(c.arr, v)
+	if found {
+		return false
+	}
+	if len(c.arr) >= maxArrayCard {
+		c.toBitmap()
+		return c.add(v)
+	}
+	c.arr = slices.Insert(c.arr, pos, v)
+	c.card++
+	return true
+}
+
+func (c *container) remove(v uint16) bool {
+	if c.bits != nil {
+		w, mask := v>>6, uint64(1)<<(v&63)
+		if c.bits[w]&mask == 0 {
+			return false
+		}
+		c.bits[w] &^= mask
+		c.card--
+		c.normalize()
+		return true
+	}
+	pos, found := slices.BinarySearch(c.arr, v)
+	if !found {
+		return false
+	}
+	c.arr = slices.Delete(c.arr, pos, pos+1)
+	c.card--
+	return true
+}
+
+func (c *container) max() uint16 {
+	if c.bits == nil {
+		return c.arr[len(c.arr)-1]
+	}
+	for w := len(c.bits) - 1; ; w-- {
+		if word := c.bits[w]; word != 0 {
+			return uint16(w*64 + 63 - bits.LeadingZeros64(word))
+		}
+	}
+}
+
+func (c *container) forEach(fn func(uint16)) {
+	if c.bits == nil {
+		for _, v := range c.arr {
+			fn(v)
+		}
+		return
+	}
+	for w, word := range c.bits {
+		for word != 0 {
+			t := bits.TrailingZeros64(word)
+			fn(uint16(w*64 + t))
+			word &= word - 1
+		}
+	}
+}
+
+func (c *container) equal(o *container) bool {
+	if c.card != o.card {
+		return false
+	}
+	if c.bits != nil && o.bits != nil {
+		return *c.bits == *o.bits
+	}
+	if c.bits == nil && o.bits == nil {
+		return slices.Equal(c.arr, o.arr)
+	}
+	return false // normalized containers of equal cardinality have equal kind
+}
+
+// union adds all values of the other container.
+func (c *container) union(o *container) {
+	if c.bits == nil && o.bits == nil && c.card+o.card <= maxArrayCard {
+		c.arr = mergeArrays(c.arr, o.arr)
+		c.card = len(c.arr)
+		return
+	}
+	if c.bits == nil {
+		c.toBitmap()
+	}
+	if o.bits != nil {
+		for w, word := range o.bits {
+			c.bits[w] |= word
+		}
+	} else {
+		for _, v := range o.arr {
+			c.bits[v>>6] |= 1 << (v & 63)
+		}
+	}
+	c.recount()
+}
+
+// intersect removes all values that are not in the other container. It
+// returns false, if the container becomes empty.
+func (c *container) intersect(o *container) bool {
+	switch {
+	case c.bits == nil && o.bits == nil:
+		topos, cpos, opos := 0, 0, 0
+		for cpos < len(c.arr) && opos < len(o.arr) {
+			switch cv, ov := c.arr[cpos], o.arr[opos]; {
+			case cv < ov:
+				cpos++
+			case cv > ov:
+				opos++
+			default:
+				c.arr[topos] = cv
+				topos++
+				cpos++
+				opos++
+			}
+		}
+		c.arr = c.arr[:topos]
+		c.card = topos
+	case c.bits == nil:
+		c.arr = slices.DeleteFunc(c.arr, func(v uint16) bool { return !o.contains(v) })
+		c.card = len(c.arr)
+	case o.bits == nil:
+		arr := make([]uint16, 0, len(o.arr))
+		for _, v := range o.arr {
+			if c.contains(v) {
+				arr = append(arr, v)
+			}
+		}
+		c.arr, c.bits, c.card = arr, nil, len(arr)
+	default:
+		for w, word := range o.bits {
+			c.bits[w] &= word
+		}
+		c.recount()
+	}
+	return c.card > 0
+}
+
+// andNot removes all values that are in the other container. It returns
+// false, if the container becomes empty.
+func (c *container) andNot(o *container) bool {
+	switch {
+	case c.bits == nil:
+		c.arr = slices.DeleteFunc(c.arr, o.contains)
+		c.card = len(c.arr)
+	case o.bits == nil:
+		for _, v := range o.arr {
+			c.bits[v>>6] &^= 1 << (v & 63)
+		}
+		c.recount()
+	default:
+		for w, word := range o.bits {
+			c.bits[w] &^= word
+		}
+		c.recount()
+	}
+	return c.card > 0
+}
+
+// recount computes the cardinality of a bitmap container and normalizes it.
+func (c *container) recount() {
+	card := 0
+	for _, word := range c.bits {
+		card += bits.OnesCount64(word)
+	}
+	c.card = card
+	c.normalize()
+}
+
+// normalize transforms a bitmap container into an array container, if it has
+// only few elements.
+func (c *container) normalize() {
+	if c.bits != nil && c.card <= maxArrayCard {
+		arr := make([]uint16, 0, c.card)
+		c.forEach(func(v uint16) { arr = append(arr, v) })
+		c.arr, c.bits = arr, nil
+	}
+}
+
+func (c *container) toBitmap() {
+	var words [bitmapWords]uint64
+	for _, v := range c.arr {
+		words[v>>6] |= 1 << (v & 63)
+	}
+	c.arr, c.bits = nil, &words
+}
+
+func mergeArrays(a, b []uint16) []uint16 {
+	result := make([]uint16, 0, len(a)+len(b))
+	apos, bpos := 0, 0
+	for apos < len(a) && bpos < len(b) {
+		switch av, bv := a[apos], b[bpos]; {
+		case av < bv:
+			result = append(result, av)
+			apos++
+		case av > bv:
+			result = append(result, bv)
+			bpos++
+		default:
+			result = append(result, av)
+			apos++
+			bpos++
+		}
+	}
+	result = append(result, a[apos:]...)
+	return append(result, b[bpos:]...)
+}
@@ -0,0 +1,182 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+)
+
+func TestBitmapSmall(t *testing.T) {
+	t.Parallel()
+	var b *idset.Bitmap
+	if !b.IsEmpty() || b.Length() != 0 || b.Contains(1) || !b.ContainsOrNil(1) {
+		t.Error("nil bitmap must be empty")
+	}
+	b = b.Add(3).Add(1).Add(id.ZidDefaultHome).Add(1)
+	if got := b.String(); got != "{00000000000001 00000000000003 00010000000000}" {
+		t.Errorf("unexpected bitmap %v", got)
+	}
+	if got := b.Length(); got != 3 {
+		t.Errorf("expected length 3, but got %d", got)
+	}
+	if !b.Set().Equal(idset.New(1, 3, id.ZidDefaultHome)) || !b.Equal(b.Set().Bitmap()) {
+		t.Error("conversion failed")
+	}
+	if zid, ok := b.Pop(); !ok || zid != id.ZidDefaultHome {
+		t.Errorf("expected pop of %v, but got %v/%v", id.ZidDefaultHome, zid, ok)
+	}
+	if got := b.Remove(1).Remove(3); got != nil {
+		t.Errorf("expected empty set after remove, but got %v", got)
+	}
+}
+
+// TestBitmapRandom compares bitmaps with sets, using random data that
+// results in both kinds of containers.
+func TestBitmapRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewPCG(4711, 17))
+	randomZids := func(n int, limit uint64) []id.Zid {
+		result := make([]id.Zid, n)
+		for i := range result {
+			result[i] = id.Zid(rnd.Uint64N(limit) + 1)
+		}
+		return result
+	}
+	for i := range 40 {
+		limit := uint64(1) << (10 + i%20)
+		zids1, zids2 := randomZids(rnd.IntN(10000), limit), randomZids(rnd.IntN(10000), limit)
+		s1, s2 := idset.New(zids1...), idset.New(zids2...)
+		b1, b2 := idset.NewBitmap(zids1...), idset.NewBitmap(zids2...)
+		checkBitmap(t, i, "new", b1, s1)
+
+		checkBitmap(t, i, "union", b1.Clone().IUnion(b2), s1.Clone().IUnion(s2))
+		checkBitmap(t, i, "intersect", b1.Clone().IntersectOrSet(b2), s1.Clone().IntersectOrSet(s2))
+		bs, ss := b1.Clone(), s1.Clone()
+		bs.ISubstract(b2)
+		ss.ISubstract(s2)
+		checkBitmap(t, i, "substract", bs, ss)
+		bNew, bRem := b1.Diff(b2)
+		sNew, sRem := s1.Diff(s2)
+		checkBitmap(t, i, "diff new", bNew, sNew)
+		checkBitmap(t, i, "diff rem", bRem, sRem)
+
+		for _, zid := range zids2 {
+			b1 = b1.Remove(zid)
+			s1 = s1.Remove(zid)
+		}
+		checkBitmap(t, i, "remove", b1, s1)
+		for _, zid := range zids2[:len(zids2)/2] {
+			if b2.Contains(zid) != s2.Contains(zid) {
+				t.Errorf("%d: contains %v differs", i, zid)
+			}
+		}
+	}
+}
+
+func checkBitmap(t *testing.T, i int, op string, b *idset.Bitmap, s *idset.Set) {
+	t.Helper()
+	if got, exp := b.SafeSorted(), s.SafeSorted(); !slices.Equal(got, exp) {
+		t.Errorf("%d: %s: expected %d elements, but got %d", i, op, len(exp), len(got))
+	}
+	if got, exp := b.Length(), s.Length(); got != exp {
+		t.Errorf("%d: %s: expected length %d, but got %d", i, op, exp, got)
+	}
+	if !b.Equal(s.Bitmap()) {
+		t.Errorf("%d: %s: bitmaps are not equal", i, op)
+	}
+}
+
+// benchZids returns n zettel identifier, created approximately every minute.
+func benchZids(n int, seed uint64) []id.Zid {
+	rnd := rand.New(rand.NewPCG(seed, seed))
+	zid := id.MustParse("20200101000000")
+	result := make([]id.Zid, n)
+	for i := range result {
+		zid += id.Zid(rnd.IntN(120) + 1)
+		result[i] = zid
+	}
+	rnd.Shuffle(n, func(i, j int) { result[i], result[j] = result[j], result[i] })
+	return result
+}
+
+const benchSize = 200000 // Size of a large Zettelstore
+
+func BenchmarkSetAddRandom(b *testing.B) {
+	zids := benchZids(benchSize, 1)
+	for b.Loop() {
+		idset.New(zids...)
+	}
+}
+
+func BenchmarkBitmapAddRandom(b *testing.B) {
+	zids := benchZids(benchSize, 1)
+	for b.Loop() {
+		idset.NewBitmap(zids...)
+	}
+}
+
+func BenchmarkSetIntersect(b *testing.B) {
+	s1, s2 := idset.New(benchZids(benchSize, 1)...), idset.New(benchZids(benchSize, 2)...)
+	for b.Loop() {
+		s1.Clone().IntersectOrSet(s2)
+	}
+}
+
+func BenchmarkBitmapIntersect(b *testing.B) {
+	b1, b2 := idset.NewBitmap(benchZids(benchSize, 1)...), idset.NewBitmap(benchZids(benchSize, 2)...)
+	for b.Loop() {
+		b1.Clone().IntersectOrSet(b2)
+	}
+}
+
+func BenchmarkSetUnion(b *testing.B) {
+	s1, s2 := idset.New(benchZids(benchSize, 1)...), idset.New(benchZids(benchSize, 2)...)
+	for b.Loop() {
+		s1.Clone().IUnion(s2)
+	}
+}
+
+func BenchmarkBitmapUnion(b *testing.B) {
+	b1, b2 := idset.NewBitmap(benchZids(benchSize, 1)...), idset.NewBitmap(benchZids(benchSize, 2)...)
+	for b.Loop() {
+		b1.Clone().IUnion(b2)
+	}
+}
+
+func BenchmarkSetRemove(b *testing.B) {
+	zids := benchZids(benchSize, 1)
+	s := idset.New(zids...)
+	for b.Loop() {
+		sc := s.Clone()
+		for _, zid := range zids[:1000] {
+			sc.Remove(zid)
+		}
+	}
+}
+
+func BenchmarkBitmapRemove(b *testing.B) {
+	zids := benchZids(benchSize, 1)
+	bm := idset.NewBitmap(zids...)
+	for b.Loop() {
+		bc := bm.Clone()
+		for _, zid := range zids[:1000] {
+			bc.Remove(zid)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"t73f.de/r/zsc/domain/id"
+)
+
+// binaryVersion is the first byte of the binary encoding of a set. It is
+// followed by the number of elements and the differences between adjacent
+// elements, each encoded as an unsigned varint.
+const binaryVersion = 1
+
+// maxZid is the largest valid zettel identifier.
+const maxZid = id.Zid(99999999999999)
+
+// ErrInvalidEncoding is returned, if an encoded set could not be decoded.
+var ErrInvalidEncoding = errors.New("idset: invalid encoding")
+
+// MarshalBinary encodes the set in a compact binary form.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	var seq []id.Zid
+	if s != nil {
+		seq = s.seq
+	}
+	result := make([]byte, 0, 1+binary.MaxVarintLen64+3*len(seq))
+	result = append(result, binaryVersion)
+	result = binary.AppendUvarint(result, uint64(len(seq)))
+	prev := id.Invalid
+	for _, zid := range seq {
+		result = binary.AppendUvarint(result, uint64(zid-prev))
+		prev = zid
+	}
+	return result, nil
+}
+
+// UnmarshalBinary replaces the elements of the set with the decoded data.
+func (s *Set) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return ErrInvalidEncoding
+	}
+	data = data[1:]
+	n, l := binary.Uvarint(data)
+	if l <= 0 || n > uint64(len(data)) {
+		return ErrInvalidEncoding
+	}
+	data = data[l:]
+	seq := make([]id.Zid, 0, n)
+	prev := id.Invalid
+	for range n {
+		delta, l := binary.Uvarint(data)
+		if l <= 0 || delta == 0 || delta > uint64(maxZid-prev) {
+			return ErrInvalidEncoding
+		}
+		data = data[l:]
+		zid := prev + id.Zid(delta)
+		seq = append(seq, zid)
+		prev = zid
+	}
+	if len(data) > 0 {
+		return ErrInvalidEncoding
+	}
+	s.seq = seq
+	return nil
+}
+
+// MarshalText encodes the set as a space-separated list of zettel
+// identifier, like MetaString.
+func (s *Set) MarshalText() ([]byte, error) { return []byte(s.MetaString()), nil }
+
+// UnmarshalText replaces the elements of the set with the decoded list of
+// zettel identifier. The list may be unsorted and may contain duplicates.
+func (s *Set) UnmarshalText(data []byte) error {
+	fields := bytes.Fields(data)
+	seq := make([]id.Zid, 0, len(fields))
+	for _, field := range fields {
+		zid, err := id.Parse(string(field))
+		if err != nil {
+			return fmt.Errorf("idset: invalid zettel identifier %q: %w", field, err)
+		}
+		seq = append(seq, zid)
+	}
+	slices.Sort(seq)
+	s.seq = slices.Compact(seq)
+	return nil
+}
+
+// MarshalBinary encodes the set in a compact binary form.
+func (f *Frozen) MarshalBinary() ([]byte, error) { return f.unfrozen().MarshalBinary() }
+
+// MarshalText encodes the set as a space-separated list of zettel identifier.
+func (f *Frozen) MarshalText() ([]byte, error) { return f.unfrozen().MarshalText() }
@@ -0,0 +1,113 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset_test
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+)
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+	testcases := []*idset.Set{
+		nil,
+		idset.New(),
+		idset.New(1),
+		idset.New(id.ZidDefaultHome, 20260101120000, 20260101120001, 99999999999999),
+	}
+	for i, s := range testcases {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Errorf("%d: binary: %v", i, err)
+			continue
+		}
+		var got idset.Set
+		if err = got.UnmarshalBinary(data); err != nil {
+			t.Errorf("%d: binary: %v", i, err)
+			continue
+		}
+		if !slices.Equal(got.SafeSorted(), s.SafeSorted()) {
+			t.Errorf("%d: binary: expected %v, but got %v", i, s, &got)
+		}
+
+		text, err := s.Freeze().MarshalText()
+		if err != nil {
+			t.Errorf("%d: text: %v", i, err)
+			continue
+		}
+		got = idset.Set{}
+		if err = got.UnmarshalText(text); err != nil {
+			t.Errorf("%d: text: %v", i, err)
+			continue
+		}
+		if got.MetaString() != s.MetaString() {
+			t.Errorf("%d: text: expected %v, but got %v", i, s, &got)
+		}
+	}
+
+	var s idset.Set
+	if err := s.UnmarshalText([]byte("00000000000002 00000000000001 00000000000002")); err != nil || s.String() != "{00000000000001 00000000000002}" {
+		t.Errorf("unsorted text: got %v / %v", &s, err)
+	}
+	if err := s.UnmarshalText([]byte("00000000000001 abc")); err == nil {
+		t.Error("invalid text must result in an error")
+	}
+	overflow := binary.AppendUvarint([]byte{1, 2, 2}, math.MaxUint64) // 2 + delta wraps around to 1
+	for i, data := range [][]byte{nil, {2, 0}, {1, 2, 1}, {1, 1, 1, 0}, {1, 1, 0}, overflow} {
+		if err := s.UnmarshalBinary(data); err != idset.ErrInvalidEncoding {
+			t.Errorf("%d: expected ErrInvalidEncoding for %v, but got %v", i, data, err)
+		}
+	}
+}
+
+func TestFrozen(t *testing.T) {
+	t.Parallel()
+	s := idset.New(1, 2, 3)
+	f := s.Freeze()
+	s.Add(4)
+	if f.Length() != 3 || f.Contains(4) {
+		t.Errorf("frozen set must not change, but got %v", f)
+	}
+	m := f.Set().Add(5)
+	if f.Contains(5) || !m.Contains(5) {
+		t.Errorf("mutable copy must be independent, but got %v / %v", f, m)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			count := 0
+			for zid := range f.All() {
+				if f.Contains(zid) {
+					count++
+				}
+			}
+			if count != 3 {
+				t.Errorf("expected 3 elements, but got %d", count)
+			}
+		})
+	}
+	wg.Wait()
+
+	var nilFrozen *idset.Frozen
+	if !nilFrozen.IsEmpty() || nilFrozen.Contains(1) || !nilFrozen.ContainsOrNil(1) || nilFrozen.Set() != nil {
+		t.Error("nil frozen set must be empty")
+	}
+}
@@ -0,0 +1,93 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset
+
+import (
+	"iter"
+	"slices"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+// Frozen is an immutable set of zettel identifier. Since it cannot be
+// modified, it can be shared between goroutines without synchronization.
+// A nil *Frozen is an empty set.
+type Frozen struct {
+	seq []id.Zid
+}
+
+// Freeze returns an immutable copy of the set.
+func (s *Set) Freeze() *Frozen {
+	if s == nil {
+		return nil
+	}
+	return &Frozen{seq: slices.Clip(slices.Clone(s.seq))}
+}
+
+// Set returns a mutable copy of the frozen set.
+func (f *Frozen) Set() *Set {
+	if f == nil {
+		return nil
+	}
+	return &Set{seq: slices.Clone(f.seq)}
+}
+
+// String returns a string representation of the set.
+func (f *Frozen) String() string { return f.unfrozen().String() }
+
+// MetaString returns a string representation of the set to be stored as metadata.
+func (f *Frozen) MetaString() string { return f.unfrozen().MetaString() }
+
+// MetaValue returns a metadata value representation of the set.
+func (f *Frozen) MetaValue() meta.Value { return f.unfrozen().MetaValue() }
+
+// IsEmpty returns true, if the set conains no element.
+func (f *Frozen) IsEmpty() bool { return f == nil || len(f.seq) == 0 }
+
+// Length returns the number of elements in this set.
+func (f *Frozen) Length() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.seq)
+}
+
+// Contains return true if the set is non-nil and the set contains the given Zettel identifier.
+func (f *Frozen) Contains(zid id.Zid) bool { return f.unfrozen().Contains(zid) }
+
+// ContainsOrNil return true if the set is nil or if the set contains the given Zettel identifier.
+func (f *Frozen) ContainsOrNil(zid id.Zid) bool { return f == nil || f.unfrozen().contains(zid) }
+
+// SafeSorted returns the set as a new sorted slice of zettel identifier.
+func (f *Frozen) SafeSorted() []id.Zid { return f.unfrozen().SafeSorted() }
+
+// Equal returns true if the other set is equal to the given set.
+func (f *Frozen) Equal(other *Frozen) bool { return f.unfrozen().Equal(other.unfrozen()) }
+
+// All returns an iterator over all elements of the set, in ascending order.
+func (f *Frozen) All() iter.Seq[id.Zid] { return f.unfrozen().All() }
+
+// Between returns an iterator over all elements of the set that are greater
+// or equal to from, and less than to, in ascending order.
+func (f *Frozen) Between(from, to id.Zid) iter.Seq[id.Zid] { return f.unfrozen().Between(from, to) }
+
+// unfrozen returns a set that shares the elements with the frozen set. Only
+// methods that do not modify the set are allowed to be called on it.
+func (f *Frozen) unfrozen() *Set {
+	if f == nil {
+		return nil
+	}
+	return &Set{seq: f.seq}
+}
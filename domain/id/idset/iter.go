@@ -0,0 +1,199 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+
+	"t73f.de/r/zsc/domain/id"
+)
+
+// All returns an iterator over all elements of the set, in ascending order.
+//
+// The set must not be modified while iterating.
+func (s *Set) All() iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		if s != nil {
+			yieldAll(s.seq, yield)
+		}
+	}
+}
+
+// Between returns an iterator over all elements of the set that are greater
+// or equal to from, and less than to, in ascending order.
+func (s *Set) Between(from, to id.Zid) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		if s != nil {
+			yieldAll(between(s.seq, from, to), yield)
+		}
+	}
+}
+
+// All returns an iterator over all elements of the set, in ascending order.
+//
+// The set must not be modified while iterating.
+func (b *Bitmap) All() iter.Seq[id.Zid] { return b.Between(0, id.Zid(1)<<47) }
+
+// Between returns an iterator over all elements of the set that are greater
+// or equal to from, and less than to, in ascending order.
+func (b *Bitmap) Between(from, to id.Zid) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		if b == nil || from >= to {
+			return
+		}
+		fromKey, _ := splitZid(from)
+		pos, _ := slices.BinarySearch(b.keys, fromKey)
+		for ; pos < len(b.keys); pos++ {
+			high := id.Zid(b.keys[pos]) << 16
+			if high >= to {
+				return
+			}
+			for low := range b.conts[pos].all() {
+				zid := high | id.Zid(low)
+				if zid < from {
+					continue
+				}
+				if zid >= to || !yield(zid) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect returns a new set with all elements of the given iterator.
+func Collect(seq iter.Seq[id.Zid]) *Set {
+	result := slices.Collect(seq)
+	if !slices.IsSorted(result) {
+		slices.Sort(result)
+	}
+	return &Set{seq: slices.Compact(result)}
+}
+
+// Union returns a lazy iterator over all elements that are in one of the
+// given iterators. Both iterators must produce ascending elements, e.g. by
+// [Set.All], and so does the result.
+func Union(seq1, seq2 iter.Seq[id.Zid]) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		next2, stop2 := iter.Pull(seq2)
+		defer stop2()
+		zid2, ok2 := next2()
+		for zid1 := range seq1 {
+			for ok2 && zid2 < zid1 {
+				if !yield(zid2) {
+					return
+				}
+				zid2, ok2 = next2()
+			}
+			if ok2 && zid2 == zid1 {
+				zid2, ok2 = next2()
+			}
+			if !yield(zid1) {
+				return
+			}
+		}
+		for ok2 {
+			if !yield(zid2) {
+				return
+			}
+			zid2, ok2 = next2()
+		}
+	}
+}
+
+// Intersection returns a lazy iterator over all elements that are in both
+// iterators. Both iterators must produce ascending elements, and so does the
+// result.
+func Intersection(seq1, seq2 iter.Seq[id.Zid]) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		next2, stop2 := iter.Pull(seq2)
+		defer stop2()
+		zid2, ok2 := next2()
+		for zid1 := range seq1 {
+			for ok2 && zid2 < zid1 {
+				zid2, ok2 = next2()
+			}
+			if !ok2 {
+				return
+			}
+			if zid2 == zid1 && !yield(zid1) {
+				return
+			}
+		}
+	}
+}
+
+// Difference returns a lazy iterator over all elements of the first
+// iterator that are not in the second. Both iterators must produce ascending
+// elements, and so does the result.
+func Difference(seq1, seq2 iter.Seq[id.Zid]) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		next2, stop2 := iter.Pull(seq2)
+		defer stop2()
+		zid2, ok2 := next2()
+		for zid1 := range seq1 {
+			for ok2 && zid2 < zid1 {
+				zid2, ok2 = next2()
+			}
+			if ok2 && zid2 == zid1 {
+				continue
+			}
+			if !yield(zid1) {
+				return
+			}
+		}
+	}
+}
+
+func yieldAll(seq []id.Zid, yield func(id.Zid) bool) {
+	for _, zid := range seq {
+		if !yield(zid) {
+			return
+		}
+	}
+}
+
+// between returns the sub-slice of the sorted slice with all elements in the
+// range [from, to).
+func between(seq []id.Zid, from, to id.Zid) []id.Zid {
+	if from >= to {
+		return nil
+	}
+	start, _ := slices.BinarySearch(seq, from)
+	end, _ := slices.BinarySearch(seq[start:], to)
+	return seq[start : start+end]
+}
+
+func (c *container) all() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		if c.bits == nil {
+			for _, v := range c.arr {
+				if !yield(v) {
+					return
+				}
+			}
+			return
+		}
+		for w, word := range c.bits {
+			for word != 0 {
+				if !yield(uint16(w*64 + bits.TrailingZeros64(word))) {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package idset_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+)
+
+func TestSetBetween(t *testing.T) {
+	t.Parallel()
+	s := idset.New(1, 3, 5, 7, 70000, 140000)
+	testcases := []struct {
+		from, to id.Zid
+		exp      []id.Zid
+	}{
+		{0, 200000, []id.Zid{1, 3, 5, 7, 70000, 140000}},
+		{3, 7, []id.Zid{3, 5}},
+		{2, 8, []id.Zid{3, 5, 7}},
+		{7, 70001, []id.Zid{7, 70000}},
+		{8, 70000, nil},
+		{5, 5, nil},
+		{7, 3, nil},
+	}
+	for i, tc := range testcases {
+		if got := slices.Collect(s.Between(tc.from, tc.to)); !slices.Equal(got, tc.exp) {
+			t.Errorf("%d: Set.Between(%v, %v) should be %v, but got %v", i, tc.from, tc.to, tc.exp, got)
+		}
+		if got := slices.Collect(s.Bitmap().Between(tc.from, tc.to)); !slices.Equal(got, tc.exp) {
+			t.Errorf("%d: Bitmap.Between(%v, %v) should be %v, but got %v", i, tc.from, tc.to, tc.exp, got)
+		}
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, s.SafeSorted()) {
+		t.Errorf("Set.All() should be %v, but got %v", s, got)
+	}
+	if got := slices.Collect(s.Bitmap().All()); !slices.Equal(got, s.SafeSorted()) {
+		t.Errorf("Bitmap.All() should be %v, but got %v", s, got)
+	}
+	var nilSet *idset.Set
+	if got := slices.Collect(nilSet.All()); got != nil {
+		t.Errorf("nil set should be empty, but got %v", got)
+	}
+}
+
+func TestLazyAlgebra(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		s1, s2             *idset.Set
+		union, inter, diff []id.Zid
+	}{
+		{nil, nil, nil, nil, nil},
+		{idset.New(1), nil, []id.Zid{1}, nil, []id.Zid{1}},
+		{nil, idset.New(1), []id.Zid{1}, nil, nil},
+		{idset.New(1, 2, 3), idset.New(2, 3, 4), []id.Zid{1, 2, 3, 4}, []id.Zid{2, 3}, []id.Zid{1}},
+		{idset.New(2, 4, 6), idset.New(1, 3, 5, 7), []id.Zid{1, 2, 3, 4, 5, 6, 7}, nil, []id.Zid{2, 4, 6}},
+	}
+	for i, tc := range testcases {
+		if got := slices.Collect(idset.Union(tc.s1.All(), tc.s2.All())); !slices.Equal(got, tc.union) {
+			t.Errorf("%d: Union(%v, %v) should be %v, but got %v", i, tc.s1, tc.s2, tc.union, got)
+		}
+		if got := slices.Collect(idset.Intersection(tc.s1.All(), tc.s2.All())); !slices.Equal(got, tc.inter) {
+			t.Errorf("%d: Intersection(%v, %v) should be %v, but got %v", i, tc.s1, tc.s2, tc.inter, got)
+		}
+		if got := slices.Collect(idset.Difference(tc.s1.All(), tc.s2.All())); !slices.Equal(got, tc.diff) {
+			t.Errorf("%d: Difference(%v, %v) should be %v, but got %v", i, tc.s1, tc.s2, tc.diff, got)
+		}
+	}
+
+	// Iteration must stop early, even if there are remaining elements.
+	for zid := range idset.Union(idset.New(1, 3).All(), idset.New(2, 4).All()) {
+		if zid > 2 {
+			t.Errorf("unexpected element %v", zid)
+		}
+		if zid == 2 {
+			break
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	got := idset.Collect(slices.Values([]id.Zid{3, 1, 3, 2}))
+	if exp := idset.New(1, 2, 3); !got.Equal(exp) {
+		t.Errorf("Collect should be %v, but got %v", exp, got)
+	}
+}
@@ -0,0 +1,127 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package id
+
+import (
+	"strings"
+	"time"
+)
+
+// FromTime returns the zettel identifier of the given point in time, in the
+// time zone of t. Fractions of a second are ignored. If the year of t is not
+// in the range 0001..9999, Invalid is returned.
+func FromTime(t time.Time) Zid {
+	if y := t.Year(); y < 1 || 9999 < y {
+		return Invalid
+	}
+	zid, err := Parse(t.Format(TimestampLayout))
+	if err != nil {
+		return Invalid
+	}
+	return zid
+}
+
+// IsTimestamp returns true, if the zettel identifier encodes a point in time.
+// Predefined zettel identifier, up to [ZidDefaultHome], are not timestamps.
+func (zid Zid) IsTimestamp() bool {
+	_, ok := zid.Time(time.UTC)
+	return ok
+}
+
+// Time returns the point in time encoded by the zettel identifier, in the
+// given time zone. If loc is nil, the local time zone is used. The boolean
+// result is false, if the identifier is not a timestamp.
+func (zid Zid) Time(loc *time.Location) (time.Time, bool) {
+	if zid <= ZidDefaultHome || !zid.IsValid() {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	t, err := time.ParseInLocation(TimestampLayout, zid.String(), loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Range is the half-open interval [From, To) of zettel identifier.
+type Range struct {
+	From Zid // First identifier of the range
+	To   Zid // First identifier after the range
+}
+
+// NewRange returns the range of all zettel identifier created at or after
+// from, and before to. If to is after the year 9999, the range contains all
+// identifier starting with from.
+func NewRange(from, to time.Time) Range {
+	r := Range{From: FromTime(from), To: FromTime(to)}
+	if to.Year() > 9999 {
+		r.To = maxZid + 1
+	}
+	return r
+}
+
+// YearRange returns the range of all zettel identifier created in the given
+// year.
+func YearRange(year int, loc *time.Location) Range {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, locOrLocal(loc))
+	return NewRange(from, from.AddDate(1, 0, 0))
+}
+
+// MonthRange returns the range of all zettel identifier created in the given
+// month.
+func MonthRange(year int, month time.Month, loc *time.Location) Range {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, locOrLocal(loc))
+	return NewRange(from, from.AddDate(0, 1, 0))
+}
+
+// DayRange returns the range of all zettel identifier created on the given
+// day.
+func DayRange(year int, month time.Month, day int, loc *time.Location) Range {
+	from := time.Date(year, month, day, 0, 0, 0, 0, locOrLocal(loc))
+	return NewRange(from, from.AddDate(0, 0, 1))
+}
+
+func locOrLocal(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Contains returns true, if the zettel identifier is within the range.
+func (r Range) Contains(zid Zid) bool { return r.From <= zid && zid < r.To }
+
+// IsEmpty returns true, if the range contains no zettel identifier.
+func (r Range) IsEmpty() bool { return r.From >= r.To }
+
+// Query returns a query expression that selects all zettel of the range,
+// e.g. "id>20250228235959 id<20250401000000".
+func (r Range) Query() string {
+	var terms []string
+	if r.From > 1 {
+		before := r.From - 1
+		if t, ok := r.From.Time(time.UTC); ok {
+			if zid := FromTime(t.Add(-time.Second)); zid != Invalid {
+				before = zid
+			}
+		}
+		terms = append(terms, "id>"+before.String())
+	}
+	if r.To <= maxZid {
+		terms = append(terms, "id<"+r.To.String())
+	}
+	return strings.Join(terms, " ")
+}
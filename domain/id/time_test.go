@@ -0,0 +1,97 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore Client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package id_test
+
+import (
+	"testing"
+	"time"
+
+	"t73f.de/r/zsc/domain/id"
+)
+
+func TestZidTime(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		zid id.Zid
+		exp string
+	}{
+		{id.ZidVersion, ""},
+		{id.ZidTemplateNewUser, ""},
+		{id.ZidAppDirectory, ""},
+		{id.ZidDefaultHome, ""},
+		{id.MustParse("20251332000000"), ""},
+		{id.MustParse("20250315123456"), "2025-03-15T12:34:56Z"},
+		{id.MustParse("00020101000000"), "0002-01-01T00:00:00Z"},
+	}
+	for i, tc := range testCases {
+		got, ok := tc.zid.Time(time.UTC)
+		if tc.exp == "" {
+			if ok || tc.zid.IsTimestamp() {
+				t.Errorf("%d: %v must not be a timestamp, but got %v", i, tc.zid, got)
+			}
+			continue
+		}
+		if !ok || !tc.zid.IsTimestamp() {
+			t.Errorf("%d: %v must be a timestamp", i, tc.zid)
+			continue
+		}
+		if s := got.Format(time.RFC3339); s != tc.exp {
+			t.Errorf("%d: expected %q, but got %q", i, tc.exp, s)
+		}
+		if zid := id.FromTime(got); zid != tc.zid {
+			t.Errorf("%d: FromTime: expected %v, but got %v", i, tc.zid, zid)
+		}
+	}
+
+	if zid := id.FromTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)); zid != id.Invalid {
+		t.Errorf("year 10000 must be invalid, but got %v", zid)
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		r        id.Range
+		from, to string
+		query    string
+	}{
+		{id.MonthRange(2025, time.March, time.UTC), "20250301000000", "20250401000000", "id>20250228235959 id<20250401000000"},
+		{id.MonthRange(2024, time.December, time.UTC), "20241201000000", "20250101000000", "id>20241130235959 id<20250101000000"},
+		{id.YearRange(2025, time.UTC), "20250101000000", "20260101000000", "id>20241231235959 id<20260101000000"},
+		{id.DayRange(2024, time.February, 29, time.UTC), "20240229000000", "20240301000000", "id>20240228235959 id<20240301000000"},
+		{id.Range{From: 1, To: id.ZidDefaultHome}, "00000000000001", "00010000000000", "id<00010000000000"},
+	}
+	for i, tc := range testCases {
+		if got := tc.r.From.String(); got != tc.from {
+			t.Errorf("%d: from expected %q, but got %q", i, tc.from, got)
+		}
+		if got := tc.r.To.String(); got != tc.to {
+			t.Errorf("%d: to expected %q, but got %q", i, tc.to, got)
+		}
+		if got := tc.r.Query(); got != tc.query {
+			t.Errorf("%d: query expected %q, but got %q", i, tc.query, got)
+		}
+		if !tc.r.Contains(tc.r.From) || tc.r.Contains(tc.r.To) || tc.r.IsEmpty() {
+			t.Errorf("%d: range %v is not half-open", i, tc.r)
+		}
+	}
+
+	r := id.YearRange(9999, time.UTC)
+	if got := r.Query(); got != "id>99981231235959" {
+		t.Errorf("last year: unexpected query %q", got)
+	}
+	if !r.Contains(id.MustParse("99991231235959")) {
+		t.Errorf("last year: range %v must contain last second", r)
+	}
+}
@@ -0,0 +1,161 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Change describes how the value of a key differs between two metadata.
+// An empty value denotes a missing key.
+type Change struct {
+	Key Key
+	Old Value
+	New Value
+
+	// Added and Removed contain the changed elements, if the key has a set type.
+	Added   []string
+	Removed []string
+}
+
+// Diff returns all changes of user-defined keys that transform metadata a
+// into metadata b, sorted by key. Computed keys are ignored.
+func Diff(a, b *Meta) []Change {
+	var result []Change
+	for _, key := range userKeys(a, b) {
+		oldVal, newVal := a.value(key), b.value(key)
+		if oldVal == newVal {
+			continue
+		}
+		c := Change{Key: key, Old: oldVal, New: newVal}
+		if Type(key).IsSet {
+			c.Added, c.Removed = setDiff(oldVal, newVal)
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// Conflict describes a key that was changed differently in two metadata and
+// that could not be merged automatically. An empty value denotes a missing key.
+type Conflict struct {
+	Key    Key
+	Base   Value
+	Ours   Value
+	Theirs Value
+}
+
+// Merge3 merges the changes from base to ours and from base to theirs. Values
+// of set types are merged element-wise. For all other types, the value of
+// the more recently modified metadata is taken, as specified by the key
+// "modified". If this cannot be decided, the conflict is reported and the
+// result contains the value of ours.
+//
+// Computed keys are ignored and copied from ours. Base may be nil, if both
+// metadata were created independently.
+func Merge3(base, ours, theirs *Meta) (*Meta, []Conflict) {
+	result := ours.Clone()
+	var conflicts []Conflict
+	for _, key := range userKeys(base, ours, theirs) {
+		baseVal, ourVal, theirVal := base.value(key), ours.value(key), theirs.value(key)
+		if ourVal == theirVal || theirVal == baseVal {
+			continue
+		}
+		if ourVal == baseVal {
+			result.SetNonEmpty(key, theirVal)
+			continue
+		}
+		if Type(key).IsSet {
+			result.SetNonEmpty(key, mergeSet(baseVal, ourVal, theirVal))
+			continue
+		}
+		switch lastWriter(ours, theirs) {
+		case 1: // result already contains the value of ours
+		case -1:
+			result.SetNonEmpty(key, theirVal)
+		default:
+			conflicts = append(conflicts, Conflict{Key: key, Base: baseVal, Ours: ourVal, Theirs: theirVal})
+		}
+	}
+	return result, conflicts
+}
+
+// value returns the value of the given key, or an empty value, if the key is
+// not stored. In contrast to Get, it allows nil metadata.
+func (m *Meta) value(key Key) Value {
+	if m == nil {
+		return ""
+	}
+	return m.pairs[key]
+}
+
+func userKeys(metas ...*Meta) []Key {
+	keys := make(map[Key]struct{})
+	for _, m := range metas {
+		if m == nil {
+			continue
+		}
+		for key := range m.pairs {
+			if !IsComputed(key) {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(keys))
+}
+
+func setDiff(oldVal, newVal Value) (added, removed []string) {
+	oldElems, newElems := oldVal.AsSlice(), newVal.AsSlice()
+	for _, elem := range newElems {
+		if !slices.Contains(oldElems, elem) {
+			added = append(added, elem)
+		}
+	}
+	for _, elem := range oldElems {
+		if !slices.Contains(newElems, elem) {
+			removed = append(removed, elem)
+		}
+	}
+	return added, removed
+}
+
+// mergeSet applies the changes from base to theirs on ours.
+func mergeSet(baseVal, ourVal, theirVal Value) Value {
+	added, removed := setDiff(baseVal, theirVal)
+	var result []string
+	for _, elem := range ourVal.AsSlice() {
+		if !slices.Contains(removed, elem) && !slices.Contains(result, elem) {
+			result = append(result, elem)
+		}
+	}
+	for _, elem := range added {
+		if !slices.Contains(result, elem) {
+			result = append(result, elem)
+		}
+	}
+	return Value(strings.Join(result, " "))
+}
+
+// lastWriter returns 1, if ours was modified after theirs, -1 if theirs was
+// modified after ours, and 0 if it cannot be decided.
+func lastWriter(ours, theirs *Meta) int {
+	ourTime, ourOk := ours.value(KeyModified).AsTime()
+	theirTime, theirOk := theirs.value(KeyModified).AsTime()
+	if !ourOk || !theirOk {
+		return 0
+	}
+	return ourTime.Compare(theirTime)
+}
@@ -0,0 +1,119 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	a := meta.NewWithData(id.Invalid, map[string]string{
+		meta.KeyTitle:    "Title",
+		meta.KeyTags:     "#a #b",
+		meta.KeyRole:     "zettel",
+		meta.KeyModified: "20260101120000",
+	})
+	b := meta.NewWithData(id.Invalid, map[string]string{
+		meta.KeyTitle:    "New Title",
+		meta.KeyTags:     "#b #c",
+		meta.KeyLang:     "de",
+		meta.KeyModified: "20260201120000",
+	})
+	got := meta.Diff(a, b)
+	exp := []meta.Change{
+		{Key: meta.KeyLang, New: "de"},
+		{Key: meta.KeyRole, Old: "zettel"},
+		{Key: meta.KeyTags, Old: "#a #b", New: "#b #c", Added: []string{"#c"}, Removed: []string{"#a"}},
+		{Key: meta.KeyTitle, Old: "Title", New: "New Title"},
+	}
+	if len(got) != len(exp) {
+		t.Fatalf("expected %v, but got %v", exp, got)
+	}
+	for i, c := range exp {
+		g := got[i]
+		if g.Key != c.Key || g.Old != c.Old || g.New != c.New || !slices.Equal(g.Added, c.Added) || !slices.Equal(g.Removed, c.Removed) {
+			t.Errorf("%d: expected %v, but got %v", i, c, g)
+		}
+	}
+	if got = meta.Diff(a, a.Clone()); len(got) != 0 {
+		t.Errorf("no changes expected, but got %v", got)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		base, ours, theirs map[string]string
+		exp                map[string]string
+		conflicts          []string
+	}{
+		{ // Independent changes
+			map[string]string{meta.KeyTitle: "T", meta.KeyRole: "zettel"},
+			map[string]string{meta.KeyTitle: "Ours", meta.KeyRole: "zettel"},
+			map[string]string{meta.KeyTitle: "T", meta.KeyLang: "de"},
+			map[string]string{meta.KeyTitle: "Ours", meta.KeyLang: "de"},
+			nil,
+		},
+		{ // Set merge
+			map[string]string{meta.KeyTags: "#a #b"},
+			map[string]string{meta.KeyTags: "#a #b #c"},
+			map[string]string{meta.KeyTags: "#b #d"},
+			map[string]string{meta.KeyTags: "#b #c #d"},
+			nil,
+		},
+		{ // Last writer wins
+			map[string]string{meta.KeyTitle: "T"},
+			map[string]string{meta.KeyTitle: "Ours", meta.KeyModified: "20260101120000"},
+			map[string]string{meta.KeyTitle: "Theirs", meta.KeyModified: "20260201120000"},
+			map[string]string{meta.KeyTitle: "Theirs", meta.KeyModified: "20260101120000"},
+			nil,
+		},
+		{ // Conflict
+			map[string]string{meta.KeyTitle: "T", meta.KeyRole: "zettel"},
+			map[string]string{meta.KeyTitle: "Ours"},
+			map[string]string{meta.KeyTitle: "Theirs", meta.KeyRole: "note"},
+			map[string]string{meta.KeyTitle: "Ours"},
+			[]string{meta.KeyRole, meta.KeyTitle},
+		},
+		{ // No base
+			nil,
+			map[string]string{meta.KeyTags: "#a"},
+			map[string]string{meta.KeyTags: "#b"},
+			map[string]string{meta.KeyTags: "#a #b"},
+			nil,
+		},
+	}
+	for i, tc := range testCases {
+		var base *meta.Meta
+		if tc.base != nil {
+			base = meta.NewWithData(id.Invalid, tc.base)
+		}
+		got, conflicts := meta.Merge3(base, meta.NewWithData(id.Invalid, tc.ours), meta.NewWithData(id.Invalid, tc.theirs))
+		if exp := meta.NewWithData(id.Invalid, tc.exp); !got.Equal(exp, true) {
+			t.Errorf("%d: expected %v, but got %v", i, exp.Map(), got.Map())
+		}
+		var keys []string
+		for _, c := range conflicts {
+			keys = append(keys, c.Key)
+		}
+		if !slices.Equal(keys, tc.conflicts) {
+			t.Errorf("%d: expected conflicts %v, but got %v", i, tc.conflicts, conflicts)
+		}
+	}
+}
@@ -15,11 +15,13 @@
 package meta
 
 import (
+	"fmt"
 	"iter"
 	"maps"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
@@ -27,56 +29,95 @@ import (
 	"t73f.de/r/zsc/domain/id"
 )
 
-type keyUsage int
+// KeyUsage specifies who is responsible for the value of a metadata key.
+type KeyUsage int
 
+// Values for KeyUsage.
 const (
-	_             keyUsage = iota
-	usageUser              // Key will be manipulated by the user
-	usageComputed          // Key is computed by zettelstore
-	usageProperty          // Key is computed and not stored by zettelstore
+	_             KeyUsage = iota
+	UsageUser              // Key will be manipulated by the user
+	UsageComputed          // Key is computed by zettelstore
+	UsageProperty          // Key is computed and not stored by zettelstore
 )
 
 // DescriptionKey formally describes each supported metadata key.
 type DescriptionKey struct {
 	Name    string
 	Type    *DescriptionType
-	usage   keyUsage
+	usage   KeyUsage
 	Inverse string
 }
 
+// Usage returns the usage of the metadata key.
+func (kd *DescriptionKey) Usage() KeyUsage { return kd.usage }
+
 // IsComputed returns true, if metadata is computed and not set by the user.
-func (kd *DescriptionKey) IsComputed() bool { return kd.usage >= usageComputed }
+func (kd *DescriptionKey) IsComputed() bool { return kd.usage >= UsageComputed }
 
 // IsProperty returns true, if metadata is a computed property.
-func (kd *DescriptionKey) IsProperty() bool { return kd.usage >= usageProperty }
+func (kd *DescriptionKey) IsProperty() bool { return kd.usage >= UsageProperty }
+
+var (
+	registeredKeys = make(map[string]*DescriptionKey)
+	mxKeys         sync.RWMutex
+)
 
-var registeredKeys = make(map[string]*DescriptionKey)
+// RegisterKey adds an application-specific metadata key. If an inverse key
+// is given, it must already be registered as a computed identifier set.
+//
+// It is safe to call RegisterKey concurrently with all other functions of
+// this package.
+func RegisterKey(name string, t *DescriptionType, usage KeyUsage, inverse string) error {
+	if !KeyIsValid(name) {
+		return fmt.Errorf("invalid key name %q", name)
+	}
+	if t == nil {
+		return fmt.Errorf("key %q has no type", name)
+	}
+	if usage < UsageUser || usage > UsageProperty {
+		return fmt.Errorf("key %q has invalid usage %d", name, usage)
+	}
 
-func registerKey(name string, t *DescriptionType, usage keyUsage, inverse string) {
+	mxKeys.Lock()
+	defer mxKeys.Unlock()
 	if _, ok := registeredKeys[name]; ok {
-		panic("Key '" + name + "' already defined")
+		return fmt.Errorf("key %q already defined", name)
 	}
 	if inverse != "" {
 		if t != TypeID && t != TypeIDSet {
-			panic("Inversable key '" + name + "' is not identifier type, but " + t.String())
+			return fmt.Errorf("inversable key %q is not identifier type, but %v", name, t)
 		}
 		inv, ok := registeredKeys[inverse]
 		if !ok {
-			panic("Inverse Key '" + inverse + "' not found")
+			return fmt.Errorf("inverse key %q not found", inverse)
 		}
 		if !inv.IsComputed() {
-			panic("Inverse Key '" + inverse + "' is not computed.")
+			return fmt.Errorf("inverse key %q is not computed", inverse)
 		}
 		if inv.Type != TypeIDSet {
-			panic("Inverse Key '" + inverse + "' is not an identifier set, but " + inv.Type.String())
+			return fmt.Errorf("inverse key %q is not an identifier set, but %v", inverse, inv.Type)
 		}
 	}
 	registeredKeys[name] = &DescriptionKey{name, t, usage, inverse}
+	return nil
+}
+
+func registerKey(name string, t *DescriptionType, usage KeyUsage, inverse string) {
+	if err := RegisterKey(name, t, usage, inverse); err != nil {
+		panic(err)
+	}
+}
+
+func getRegisteredKey(name string) (*DescriptionKey, bool) {
+	mxKeys.RLock()
+	kd, ok := registeredKeys[name]
+	mxKeys.RUnlock()
+	return kd, ok
 }
 
 // IsComputed returns true, if key denotes a computed metadata key.
 func IsComputed(name string) bool {
-	if kd, ok := registeredKeys[name]; ok {
+	if kd, ok := getRegisteredKey(name); ok {
 		return kd.IsComputed()
 	}
 	return false
@@ -84,7 +125,7 @@ func IsComputed(name string) bool {
 
 // IsProperty returns true, if key denotes a property metadata value.
 func IsProperty(name string) bool {
-	if kd, ok := registeredKeys[name]; ok {
+	if kd, ok := getRegisteredKey(name); ok {
 		return kd.IsProperty()
 	}
 	return false
@@ -92,7 +133,7 @@ func IsProperty(name string) bool {
 
 // Inverse returns the name of the inverse key.
 func Inverse(name string) string {
-	if kd, ok := registeredKeys[name]; ok {
+	if kd, ok := getRegisteredKey(name); ok {
 		return kd.Inverse
 	}
 	return ""
@@ -100,7 +141,7 @@ func Inverse(name string) string {
 
 // GetDescription returns the key description object of the given key name.
 func GetDescription(name string) DescriptionKey {
-	if d, ok := registeredKeys[name]; ok {
+	if d, ok := getRegisteredKey(name); ok {
 		return *d
 	}
 	return DescriptionKey{Type: Type(name)}
@@ -108,6 +149,8 @@ func GetDescription(name string) DescriptionKey {
 
 // GetSortedKeyDescriptions delivers all metadata key descriptions as a slice, sorted by name.
 func GetSortedKeyDescriptions() []*DescriptionKey {
+	mxKeys.RLock()
+	defer mxKeys.RUnlock()
 	keys := slices.Sorted(maps.Keys(registeredKeys))
 	result := make([]*DescriptionKey, 0, len(keys))
 	for _, n := range keys {
@@ -165,46 +208,46 @@ const (
 
 // Supported keys.
 func init() {
-	registerKey(KeyID, TypeID, usageComputed, "")
-	registerKey(KeyTitle, TypeEmpty, usageUser, "")
-	registerKey(KeyRole, TypeWord, usageUser, "")
-	registerKey(KeyTags, TypeTagSet, usageUser, "")
-	registerKey(KeySyntax, TypeWord, usageUser, "")
+	registerKey(KeyID, TypeID, UsageComputed, "")
+	registerKey(KeyTitle, TypeEmpty, UsageUser, "")
+	registerKey(KeyRole, TypeWord, UsageUser, "")
+	registerKey(KeyTags, TypeTagSet, UsageUser, "")
+	registerKey(KeySyntax, TypeWord, UsageUser, "")
 
 	// Properties that are inverse keys
-	registerKey(KeyFolge, TypeIDSet, usageProperty, "")
-	registerKey(KeySequel, TypeIDSet, usageProperty, "")
-	registerKey(KeySubordinate, TypeIDSet, usageProperty, "")
-	registerKey(KeySuccessor, TypeIDSet, usageProperty, "")
+	registerKey(KeyFolge, TypeIDSet, UsageProperty, "")
+	registerKey(KeySequel, TypeIDSet, UsageProperty, "")
+	registerKey(KeySubordinate, TypeIDSet, UsageProperty, "")
+	registerKey(KeySuccessor, TypeIDSet, UsageProperty, "")
 
 	// Non-inverse keys
-	registerKey(KeyAuthor, TypeString, usageUser, "")
-	registerKey(KeyBack, TypeIDSet, usageProperty, "")
-	registerKey(KeyBackward, TypeIDSet, usageProperty, "")
-	registerKey(KeyBoxNumber, TypeNumber, usageProperty, "")
-	registerKey(KeyCopyright, TypeString, usageUser, "")
-	registerKey(KeyCreated, TypeTimestamp, usageComputed, "")
-	registerKey(KeyCredential, TypeCredential, usageUser, "")
-	registerKey(KeyDead, TypeIDSet, usageProperty, "")
-	registerKey(KeyExpire, TypeTimestamp, usageUser, "")
-	registerKey(KeyFolgeRole, TypeWord, usageUser, "")
-	registerKey(KeyForward, TypeIDSet, usageProperty, "")
-	registerKey(KeyLang, TypeWord, usageUser, "")
-	registerKey(KeyLicense, TypeEmpty, usageUser, "")
-	registerKey(KeyModified, TypeTimestamp, usageComputed, "")
-	registerKey(KeyPrecursor, TypeIDSet, usageUser, KeyFolge)
-	registerKey(KeyPredecessor, TypeIDSet, usageUser, KeySuccessor)
-	registerKey(KeyPrequel, TypeIDSet, usageUser, KeySequel)
-	registerKey(KeyPublished, TypeTimestamp, usageProperty, "")
-	registerKey(KeyQuery, TypeEmpty, usageUser, "")
-	registerKey(KeyReadOnly, TypeWord, usageUser, "")
-	registerKey(KeySummary, TypeString, usageUser, "")
-	registerKey(KeySuperordinate, TypeIDSet, usageUser, KeySubordinate)
-	registerKey(KeyURL, TypeURL, usageUser, "")
-	registerKey(KeyUselessFiles, TypeString, usageProperty, "")
-	registerKey(KeyUserID, TypeWord, usageUser, "")
-	registerKey(KeyUserRole, TypeWord, usageUser, "")
-	registerKey(KeyVisibility, TypeWord, usageUser, "")
+	registerKey(KeyAuthor, TypeString, UsageUser, "")
+	registerKey(KeyBack, TypeIDSet, UsageProperty, "")
+	registerKey(KeyBackward, TypeIDSet, UsageProperty, "")
+	registerKey(KeyBoxNumber, TypeNumber, UsageProperty, "")
+	registerKey(KeyCopyright, TypeString, UsageUser, "")
+	registerKey(KeyCreated, TypeTimestamp, UsageComputed, "")
+	registerKey(KeyCredential, TypeCredential, UsageUser, "")
+	registerKey(KeyDead, TypeIDSet, UsageProperty, "")
+	registerKey(KeyExpire, TypeTimestamp, UsageUser, "")
+	registerKey(KeyFolgeRole, TypeWord, UsageUser, "")
+	registerKey(KeyForward, TypeIDSet, UsageProperty, "")
+	registerKey(KeyLang, TypeWord, UsageUser, "")
+	registerKey(KeyLicense, TypeEmpty, UsageUser, "")
+	registerKey(KeyModified, TypeTimestamp, UsageComputed, "")
+	registerKey(KeyPrecursor, TypeIDSet, UsageUser, KeyFolge)
+	registerKey(KeyPredecessor, TypeIDSet, UsageUser, KeySuccessor)
+	registerKey(KeyPrequel, TypeIDSet, UsageUser, KeySequel)
+	registerKey(KeyPublished, TypeTimestamp, UsageProperty, "")
+	registerKey(KeyQuery, TypeEmpty, UsageUser, "")
+	registerKey(KeyReadOnly, TypeWord, UsageUser, "")
+	registerKey(KeySummary, TypeString, UsageUser, "")
+	registerKey(KeySuperordinate, TypeIDSet, UsageUser, KeySubordinate)
+	registerKey(KeyURL, TypeURL, UsageUser, "")
+	registerKey(KeyUselessFiles, TypeString, UsageProperty, "")
+	registerKey(KeyUserID, TypeWord, UsageUser, "")
+	registerKey(KeyUserRole, TypeWord, UsageUser, "")
+	registerKey(KeyVisibility, TypeWord, UsageUser, "")
 }
 
 // NewPrefix is the prefix for metadata keys in template zettel for creating new zettel.
@@ -333,16 +376,18 @@ func (m *Meta) GetTitle() string {
 // and computed values.
 func (m *Meta) All() iter.Seq2[Key, Value] {
 	return func(yield func(Key, Value) bool) {
-		m.firstKeys()(yield)
-		m.restKeys(notComputedKey)(yield)
+		if m.yieldFirstKeys(yield) {
+			m.restKeys(notComputedKey)(yield)
+		}
 	}
 }
 
 // Computed returns an iterator over all key/value pairs, except the zettel identifier.
 func (m *Meta) Computed() iter.Seq2[Key, Value] {
 	return func(yield func(Key, Value) bool) {
-		m.firstKeys()(yield)
-		m.restKeys(anyKey)(yield)
+		if m.yieldFirstKeys(yield) {
+			m.restKeys(anyKey)(yield)
+		}
 	}
 }
 
@@ -362,16 +407,17 @@ func (m *Meta) ComputedRest() iter.Seq2[Key, Value] {
 	}
 }
 
-func (m *Meta) firstKeys() iter.Seq2[Key, Value] {
-	return func(yield func(Key, Value) bool) {
-		for _, key := range firstKeys {
-			if val, ok := m.pairs[key]; ok {
-				if !yield(key, val) {
-					return
-				}
+// yieldFirstKeys yields all main keys. It returns false, if the iteration
+// was stopped.
+func (m *Meta) yieldFirstKeys(yield func(Key, Value) bool) bool {
+	for _, key := range firstKeys {
+		if val, ok := m.pairs[key]; ok {
+			if !yield(key, val) {
+				return false
 			}
 		}
 	}
+	return true
 }
 
 func (m *Meta) restKeys(addKeyPred func(Key) bool) iter.Seq2[Key, Value] {
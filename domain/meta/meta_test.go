@@ -184,6 +184,19 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestAllStop(t *testing.T) {
+	t.Parallel()
+	m := New(testID)
+	m.Set(KeyTitle, "title")
+	m.Set("key", "val")
+	for key := range m.All() {
+		if key != KeyTitle {
+			t.Errorf("iteration must stop at first key, but got %q", key)
+		}
+		break
+	}
+}
+
 func TestEqual(t *testing.T) {
 	t.Parallel()
 	testcases := []struct {
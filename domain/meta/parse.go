@@ -24,12 +24,19 @@ import (
 )
 
 // NewFromInput parses the meta data of a zettel.
+//
+// If the metadata starts with a line "---" and is closed by another such line,
+// it is parsed as YAML front matter. YAML sequences are stored as a list of
+// words, where elements of tag sets get a leading '#', if needed.
 func NewFromInput(zid id.Zid, inp *input.Input) *Meta {
+	meta := New(zid)
 	if inp.Ch == '-' && inp.PeekN(0) == '-' && inp.PeekN(1) == '-' {
 		skipToEOL(inp)
 		inp.EatEOL()
+		if parseYAML(meta, inp) {
+			return meta
+		}
 	}
-	meta := New(zid)
 	for {
 		inp.SkipSpace()
 		switch inp.Ch {
@@ -186,3 +186,60 @@ func TestPrecursorIDSet(t *testing.T) {
 		}
 	}
 }
+
+func TestYAML(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		input string
+		exp   []pair
+	}{
+		{"---\ntitle: \"A: \\\"quoted\\\" title\"\n---\n", []pair{{"title", `A: "quoted" title`}}},
+		{"---\ntitle: 'It''s'\n---\n", []pair{{"title", "It's"}}},
+		{"---\ntitle: \"Foo\" and bar\n---\n", []pair{{"title", `"Foo" and bar`}}},
+		{"---\ntitle: 'a' and 'b'\n---\n", []pair{{"title", "'a' and 'b'"}}},
+		{"---\ntitle: \"unclosed\n---\n", []pair{{"title", `"unclosed`}}},
+		{"---\ntitle: 'It''s'  \n---\n", []pair{{"title", "It's"}}},
+		{"---\ntitle: a\n  long title\n\nrole: zettel\n---\n", []pair{{"title", "a long title"}, {"role", "zettel"}}},
+		{"---\ntags:\n  - Go\n  - '#yaml'\n---\n", []pair{{"tags", "#go #yaml"}}},
+		{"---\ntags:\n- a\n- b\nrole: note\n---\n", []pair{{"role", "note"}, {"tags", "#a #b"}}},
+		{"---\ntags: [b, \"a\"]\n---\n", []pair{{"tags", "#a #b"}}},
+		{"---\ntags: #x #y\n---\n", []pair{{"tags", "#x #y"}}},
+		{"---\nprecursor: [00010000000001, 00010000000002]\n---\n", []pair{{"precursor", "00010000000001 00010000000002"}}},
+		{"---\nsummary: >-\n  folded\n  text\n# comment\nlang: de\n...\n", []pair{{"lang", "de"}, {"summary", "folded text"}}},
+		{"---\nauthors:\n  - Jane\n  - John\n---\n", []pair{{"authors", "Jane John"}}},
+	}
+	for i, tc := range testcases {
+		inp := input.NewInput([]byte(tc.input + "X"))
+		m := meta.NewFromInput(testID, inp)
+		if got := iter2pairs(m.All()); !equalPairs(tc.exp, got) {
+			t.Errorf("TC=%d: expected=%v, got=%v", i, tc.exp, got)
+		}
+		if !m.YamlSep {
+			t.Errorf("TC=%d: YamlSep not set", i)
+		}
+		if inp.Ch != 'X' {
+			t.Errorf("TC=%d: input not positioned after front matter, but at %q", i, inp.Ch)
+		}
+	}
+}
+
+func TestYAMLUnclosed(t *testing.T) {
+	t.Parallel()
+	testcases := []string{
+		"---\ntitle: T\n\nSome prose.\n\n---\n\nMore prose.\n",
+		"---\ntitle: T\n\nSome prose.\n...\n",
+	}
+	for i, tc := range testcases {
+		inp := input.NewInput([]byte(tc))
+		m := meta.NewFromInput(testID, inp)
+		if m.YamlSep {
+			t.Errorf("TC=%d: YamlSep must not be set", i)
+		}
+		if got := m.GetDefault(meta.KeyTitle, ""); got != "T" {
+			t.Errorf("TC=%d: expected title %q, but got %q", i, "T", got)
+		}
+		if rest := string(inp.Src[inp.Pos:]); !strings.HasPrefix(rest, "Some prose.") {
+			t.Errorf("TC=%d: content must not be read as metadata, but got %q", i, rest)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+func TestRegisterKeyErrors(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		ty      *meta.DescriptionType
+		usage   meta.KeyUsage
+		inverse string
+	}{
+		{"Invalid_Name", meta.TypeString, meta.UsageUser, ""},
+		{"reg-no-type", nil, meta.UsageUser, ""},
+		{"reg-bad-usage", meta.TypeString, 0, ""},
+		{meta.KeyTitle, meta.TypeString, meta.UsageUser, ""},
+		{"reg-no-id", meta.TypeString, meta.UsageUser, meta.KeyBack},
+		{"reg-missing-inverse", meta.TypeIDSet, meta.UsageUser, "reg-unknown"},
+		{"reg-user-inverse", meta.TypeIDSet, meta.UsageUser, meta.KeyPrecursor},
+		{"reg-id-inverse", meta.TypeIDSet, meta.UsageUser, meta.KeyCreated},
+	}
+	for i, tc := range testCases {
+		if err := meta.RegisterKey(tc.name, tc.ty, tc.usage, tc.inverse); err == nil {
+			t.Errorf("%d: registering %q expected an error, but got none", i, tc.name)
+		}
+	}
+}
+
+// registerRun makes the keys of TestRegisterKey unique, because registered
+// keys remain registered, e.g. if the test is run with -count=2.
+var registerRun atomic.Int64
+
+func TestRegisterKey(t *testing.T) {
+	t.Parallel()
+	run := registerRun.Add(1)
+	backlinks := fmt.Sprintf("reg-backlinks-%d", run)
+	mentor := fmt.Sprintf("reg-mentor-%d", run)
+	if err := meta.RegisterKey(backlinks, meta.TypeIDSet, meta.UsageProperty, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.RegisterKey(mentor, meta.TypeID, meta.UsageUser, backlinks); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.RegisterKey(mentor, meta.TypeID, meta.UsageUser, ""); err == nil {
+		t.Error("duplicate registration must fail")
+	}
+
+	if got := meta.Type(mentor); got != meta.TypeID {
+		t.Errorf("expected type %v, but got %v", meta.TypeID, got)
+	}
+	if got := meta.Inverse(mentor); got != backlinks {
+		t.Errorf("expected inverse %q, but got %q", backlinks, got)
+	}
+	if !meta.IsProperty(backlinks) || !meta.IsComputed(backlinks) {
+		t.Errorf("%s must be a computed property", backlinks)
+	}
+	if kd := meta.GetDescription(backlinks); kd.Usage() != meta.UsageProperty {
+		t.Errorf("expected usage %v, but got %v", meta.UsageProperty, kd.Usage())
+	}
+	found := false
+	for _, kd := range meta.GetSortedKeyDescriptions() {
+		if kd.Name == mentor {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("%s not found in key descriptions", mentor)
+	}
+
+	m := meta.New(id.Invalid)
+	m.Set(mentor, "no-zid")
+	if diags := m.Validate(); len(diags) != 1 || diags[0].Key != mentor {
+		t.Errorf("expected diagnostic for %s, but got %v", mentor, diags)
+	}
+}
@@ -0,0 +1,176 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"t73f.de/r/zsc/domain/id"
+)
+
+// TagSeparator separates the levels of a hierarchical tag, like "#lang/go".
+const TagSeparator = '/'
+
+// TagNode is a tag within a hierarchy of tags. For example, "#lang/go" is a
+// child of "#lang".
+type TagNode struct {
+	Name     string     // Full name of the tag, e.g. "#lang/go"
+	Label    string     // Last part of the name, e.g. "go"
+	Count    int        // Number of zettel with exactly this tag
+	Total    int        // Number of zettel with this tag or one of its descendants
+	Children []*TagNode // Tags of the next level
+}
+
+// TagTree is the list of all tags without a parent.
+type TagTree []*TagNode
+
+// NewTagTree builds the tag hierarchy from a mapping of tags to zettel
+// identifier, e.g. from a [t73f.de/r/zsc/webapi.Aggregate] of key "tags".
+// Parent tags that are not used by any zettel have a count of zero. The
+// tree is sorted by name.
+func NewTagTree(tags map[string][]id.Zid) TagTree {
+	type tagData struct {
+		node *TagNode
+		zids map[id.Zid]struct{}
+	}
+	nodes := make(map[string]*tagData)
+	var roots TagTree
+	var getNode func(string) *tagData
+	getNode = func(name string) *tagData {
+		if td, found := nodes[name]; found {
+			return td
+		}
+		td := &tagData{node: &TagNode{Name: name, Label: name}, zids: make(map[id.Zid]struct{})}
+		nodes[name] = td
+		if pos := strings.LastIndexByte(name, TagSeparator); pos > 0 && pos < len(name)-1 {
+			td.node.Label = name[pos+1:]
+			parent := getNode(name[:pos])
+			parent.node.Children = append(parent.node.Children, td.node)
+		} else {
+			roots = append(roots, td.node)
+		}
+		return td
+	}
+
+	for name, zids := range tags {
+		td := getNode(name)
+		td.node.Count = len(slices.Compact(slices.Sorted(slices.Values(zids))))
+		for prefix := name; ; {
+			ptd := nodes[prefix]
+			for _, zid := range zids {
+				ptd.zids[zid] = struct{}{}
+			}
+			pos := strings.LastIndexByte(prefix, TagSeparator)
+			if pos <= 0 || pos == len(prefix)-1 {
+				break
+			}
+			prefix = prefix[:pos]
+		}
+	}
+	for _, td := range nodes {
+		td.node.Total = len(td.zids)
+	}
+	roots.SortByName()
+	return roots
+}
+
+// TagTree builds the tag hierarchy of an arrangement of tags.
+func (a Arrangement) TagTree() TagTree { return NewTagTree(a.zids()) }
+
+func (a Arrangement) zids() map[string][]id.Zid {
+	result := make(map[string][]id.Zid, len(a))
+	for cat, metas := range a {
+		zids := make([]id.Zid, len(metas))
+		for i, m := range metas {
+			zids[i] = m.Zid
+		}
+		result[cat] = zids
+	}
+	return result
+}
+
+// SortByName sorts all levels of the tree by the name of the tags.
+func (tt TagTree) SortByName() {
+	tt.sort(func(x, y *TagNode) int { return strings.Compare(x.Name, y.Name) })
+}
+
+// SortByCount sorts all levels of the tree by the total count, descending.
+// If two counts are equal, tags are sorted by name.
+func (tt TagTree) SortByCount() {
+	tt.sort(func(x, y *TagNode) int {
+		if c := cmp.Compare(y.Total, x.Total); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Name, y.Name)
+	})
+}
+
+func (tt TagTree) sort(cmpFn func(x, y *TagNode) int) {
+	slices.SortFunc(tt, cmpFn)
+	for _, node := range tt {
+		TagTree(node.Children).sort(cmpFn)
+	}
+}
+
+// CoOccurrence states how many zettel use two tags together.
+type CoOccurrence struct {
+	Tag1, Tag2 string // Tag1 < Tag2
+	Count      int
+}
+
+// NewCoOccurrences returns how often two tags are used together, from a
+// mapping of tags to zettel identifier. The result is sorted by count,
+// descending, and then by the tag names.
+func NewCoOccurrences(tags map[string][]id.Zid) []CoOccurrence {
+	zidTags := make(map[id.Zid][]string)
+	for _, tag := range slices.Sorted(maps.Keys(tags)) {
+		for _, zid := range tags[tag] {
+			if zt := zidTags[zid]; len(zt) == 0 || zt[len(zt)-1] != tag {
+				zidTags[zid] = append(zt, tag)
+			}
+		}
+	}
+
+	type tagPair struct{ tag1, tag2 string }
+	counts := make(map[tagPair]int)
+	for _, zt := range zidTags {
+		for i, tag1 := range zt {
+			for _, tag2 := range zt[i+1:] {
+				counts[tagPair{tag1, tag2}]++
+			}
+		}
+	}
+
+	result := make([]CoOccurrence, 0, len(counts))
+	for tp, count := range counts {
+		result = append(result, CoOccurrence{Tag1: tp.tag1, Tag2: tp.tag2, Count: count})
+	}
+	slices.SortFunc(result, func(x, y CoOccurrence) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		if c := strings.Compare(x.Tag1, y.Tag1); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Tag2, y.Tag2)
+	})
+	return result
+}
+
+// CoOccurrences returns how often two categories of the arrangement are used
+// together.
+func (a Arrangement) CoOccurrences() []CoOccurrence { return NewCoOccurrences(a.zids()) }
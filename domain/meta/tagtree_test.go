@@ -0,0 +1,70 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+func writeTagTree(sb *strings.Builder, tt meta.TagTree) {
+	for _, node := range tt {
+		fmt.Fprintf(sb, "(%s %s %d %d", node.Name, node.Label, node.Count, node.Total)
+		writeTagTree(sb, node.Children)
+		sb.WriteByte(')')
+	}
+}
+
+func TestTagTree(t *testing.T) {
+	t.Parallel()
+	var metas []*meta.Meta
+	for i, tags := range []string{"#lang/go/generics #lang/rust", "#lang/go", "#lang/go #misc", "#misc"} {
+		m := meta.New(id.Zid(i + 1))
+		m.Set(meta.KeyTags, meta.Value(tags))
+		metas = append(metas, m)
+	}
+	tt := meta.CreateArrangement(metas, meta.KeyTags).TagTree()
+
+	var sb strings.Builder
+	writeTagTree(&sb, tt)
+	exp := "(#lang #lang 0 3(#lang/go go 2 3(#lang/go/generics generics 1 1))(#lang/rust rust 1 1))(#misc #misc 2 2)"
+	if got := sb.String(); got != exp {
+		t.Errorf("expected\n%s, but got\n%s", exp, got)
+	}
+
+	tt.SortByCount()
+	sb.Reset()
+	writeTagTree(&sb, tt)
+	exp = "(#lang #lang 0 3(#lang/go go 2 3(#lang/go/generics generics 1 1))(#lang/rust rust 1 1))(#misc #misc 2 2)"
+	if got := sb.String(); got != exp {
+		t.Errorf("expected\n%s, but got\n%s", exp, got)
+	}
+
+	agg := map[string][]id.Zid{"#b": {1, 2, 3}, "#a": {1, 2}, "#c": {3, 1}}
+	tt = meta.NewTagTree(agg)
+	tt.SortByCount()
+	if got := tt[0].Name + tt[1].Name + tt[2].Name; got != "#b#a#c" {
+		t.Errorf("expected order by count, but got %q", got)
+	}
+
+	got := meta.NewCoOccurrences(agg)
+	expCo := []meta.CoOccurrence{{"#a", "#b", 2}, {"#b", "#c", 2}, {"#a", "#c", 1}}
+	if fmt.Sprint(got) != fmt.Sprint(expCo) {
+		t.Errorf("expected co-occurrences %v, but got %v", expCo, got)
+	}
+}
@@ -108,7 +108,7 @@ var (
 // Type returns a type hint for the given key. If no type hint is specified,
 // TypeEmpty is returned.
 func Type(key string) *DescriptionType {
-	if k, ok := registeredKeys[key]; ok {
+	if k, ok := getRegisteredKey(key); ok {
 		return k.Type
 	}
 	mxTypedKey.RLock()
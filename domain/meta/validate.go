@@ -0,0 +1,184 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/zsc/domain/id"
+)
+
+// Diagnostic describes a metadata value that does not fit the type of its key.
+type Diagnostic struct {
+	Key     Key
+	Value   Value
+	Message string
+
+	// Fix is a corrected value, if HasFix is true. It is only provided, if
+	// the correction does not change the meaning of the value.
+	Fix    Value
+	HasFix bool
+}
+
+func (d Diagnostic) String() string {
+	if d.HasFix {
+		return d.Key + ": " + d.Message + " (fix: " + strconv.Quote(string(d.Fix)) + ")"
+	}
+	return d.Key + ": " + d.Message
+}
+
+// Validate checks all metadata values against the type of their key. It
+// returns a diagnostic for every invalid value, sorted by key.
+func (m *Meta) Validate() []Diagnostic {
+	if m == nil {
+		return nil
+	}
+	var result []Diagnostic
+	for _, key := range slices.Sorted(maps.Keys(m.pairs)) {
+		val := m.pairs[key]
+		if !KeyIsValid(key) {
+			result = append(result, Diagnostic{Key: key, Value: val, Message: "invalid key"})
+			continue
+		}
+		if d, ok := validateValue(key, val); !ok {
+			d.Key, d.Value = key, val
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// ApplyFixes stores all fixed values of the given diagnostics. It returns the
+// number of changed values.
+func (m *Meta) ApplyFixes(diags []Diagnostic) int {
+	count := 0
+	for _, d := range diags {
+		if d.HasFix {
+			if val, found := m.Get(d.Key); found && val == d.Value {
+				m.Set(d.Key, d.Fix)
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func validateValue(key string, val Value) (Diagnostic, bool) {
+	switch key {
+	case KeyVisibility:
+		return validateEnum(val, "visibility", func(v Value) bool { return v.AsVisibility() != VisibilityUnknown })
+	case KeyUserRole:
+		return validateEnum(val, "user role", func(v Value) bool { return v.AsUserRole() != UserRoleUnknown })
+	}
+
+	switch Type(key) {
+	case TypeID:
+		if _, err := id.Parse(string(val)); err != nil {
+			return Diagnostic{Message: "invalid zettel identifier"}, false
+		}
+	case TypeIDSet:
+		for elem := range val.Fields() {
+			if _, err := id.Parse(elem); err != nil {
+				return Diagnostic{Message: "invalid zettel identifier " + strconv.Quote(elem)}, false
+			}
+		}
+	case TypeNumber:
+		if _, err := strconv.ParseInt(string(val), 10, 64); err != nil {
+			return Diagnostic{Message: "invalid number"}, false
+		}
+	case TypeTagSet:
+		return validateTags(val)
+	case TypeTimestamp:
+		return validateTimestamp(val)
+	case TypeURL:
+		return validateURL(val)
+	case TypeWord:
+		if len(val.AsSlice()) > 1 {
+			return Diagnostic{Message: "more than one word"}, false
+		}
+	}
+	return Diagnostic{}, true
+}
+
+func validateEnum(val Value, name string, isValid func(Value) bool) (Diagnostic, bool) {
+	if isValid(val) {
+		return Diagnostic{}, true
+	}
+	d := Diagnostic{Message: "unknown " + name + " " + strconv.Quote(string(val))}
+	if lower := val.ToLower(); isValid(lower) {
+		d.Fix, d.HasFix = lower, true
+	}
+	return d, false
+}
+
+func validateTags(val Value) (Diagnostic, bool) {
+	var fixed []string
+	needsFix := false
+	for elem := range val.Elems() {
+		if elem == "#" {
+			return Diagnostic{Message: "empty tag"}, false
+		}
+		if elem[0] != '#' {
+			needsFix = true
+		}
+		fixed = append(fixed, string(elem.NormalizeTag()))
+	}
+	if !needsFix {
+		return Diagnostic{}, true
+	}
+	return Diagnostic{
+		Message: "tag without '#'",
+		Fix:     Value(strings.Join(fixed, " ")),
+		HasFix:  true,
+	}, false
+}
+
+func validateTimestamp(val Value) (Diagnostic, bool) {
+	if _, ok := val.AsTime(); ok {
+		return Diagnostic{}, true
+	}
+	d := Diagnostic{Message: "unparsable timestamp"}
+
+	// A timestamp like "2026-10-18 12:34" is fixed by removing all separators.
+	digits := Value(strings.Map(func(r rune) rune {
+		switch {
+		case '0' <= r && r <= '9':
+			return r
+		case r == '-' || r == ':' || r == ' ' || r == 'T' || r == '.' || r == '_':
+			return -1
+		}
+		return 'x'
+	}, string(val)))
+	if digits != val {
+		if _, ok := digits.AsTime(); ok && len(digits) <= len(id.TimestampLayout) {
+			d.Fix, d.HasFix = digits, true
+		}
+	}
+	return d, false
+}
+
+func validateURL(val Value) (Diagnostic, bool) {
+	u, err := url.Parse(string(val))
+	if err != nil {
+		return Diagnostic{Message: "invalid URL"}, false
+	}
+	if u.Scheme == "" {
+		return Diagnostic{Message: "URL without scheme"}, false
+	}
+	return Diagnostic{}, true
+}
@@ -0,0 +1,105 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta_test
+
+import (
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		key    string
+		value  meta.Value
+		valid  bool
+		hasFix bool
+		fix    meta.Value
+	}{
+		{meta.KeyTitle, "any title", true, false, ""},
+		{meta.KeyURL, "https://zettelstore.de", true, false, ""},
+		{meta.KeyURL, "zettelstore.de", false, false, ""},
+		{meta.KeyURL, "https://zettel store.de/%zz", false, false, ""},
+		{meta.KeyCreated, "20261018123456", true, false, ""},
+		{meta.KeyCreated, "2026-10-18 12:34", false, true, "202610181234"},
+		{meta.KeyCreated, "yesterday", false, false, ""},
+		{meta.KeyCreated, "2026-13-01", false, false, ""},
+		{meta.KeyPrecursor, "00010000000000 20261018123456", true, false, ""},
+		{meta.KeyPrecursor, "00010000000000 123", false, false, ""},
+		{"my-ref", "00010000000000", true, false, ""},
+		{"my-ref", "abc", false, false, ""},
+		{meta.KeyTags, "#a #b", true, false, ""},
+		{meta.KeyTags, "#a b", false, true, "#a #b"},
+		{meta.KeyTags, "# a", false, false, ""},
+		{"my-number", "-17", true, false, ""},
+		{"my-number", "17.5", false, false, ""},
+		{meta.KeyRole, "zettel", true, false, ""},
+		{meta.KeyRole, "zettel note", false, false, ""},
+		{meta.KeyVisibility, "public", true, false, ""},
+		{meta.KeyVisibility, "Public", false, true, "public"},
+		{meta.KeyVisibility, "everyone", false, false, ""},
+		{meta.KeyUserRole, "reader", true, false, ""},
+		{meta.KeyUserRole, "OWNER", false, true, "owner"},
+		{meta.KeyUserRole, "admin", false, false, ""},
+		{"Invalid_Key", "value", false, false, ""},
+	}
+	for i, tc := range testCases {
+		m := meta.New(id.Invalid)
+		m.Set(tc.key, tc.value)
+		diags := m.Validate()
+		if tc.valid {
+			if len(diags) > 0 {
+				t.Errorf("%d: %s=%q: expected no diagnostic, but got %v", i, tc.key, tc.value, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 {
+			t.Errorf("%d: %s=%q: expected one diagnostic, but got %v", i, tc.key, tc.value, diags)
+			continue
+		}
+		d := diags[0]
+		if d.Key != tc.key || d.Value != tc.value {
+			t.Errorf("%d: diagnostic for %s=%q expected, but got %s=%q", i, tc.key, tc.value, d.Key, d.Value)
+		}
+		if d.HasFix != tc.hasFix || d.Fix != tc.fix {
+			t.Errorf("%d: %s=%q: expected fix %v/%q, but got %v/%q", i, tc.key, tc.value, tc.hasFix, tc.fix, d.HasFix, d.Fix)
+		}
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	t.Parallel()
+	m := meta.New(id.Invalid)
+	m.Set(meta.KeyTags, "a #b")
+	m.Set(meta.KeyVisibility, "Login")
+	m.Set(meta.KeyURL, "no-url")
+	diags := m.Validate()
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, but got %v", diags)
+	}
+	if got := m.ApplyFixes(diags); got != 2 {
+		t.Errorf("expected 2 fixes, but got %d", got)
+	}
+	if got, _ := m.Get(meta.KeyTags); got != "#a #b" {
+		t.Errorf("expected fixed tags, but got %q", got)
+	}
+	if got, _ := m.Get(meta.KeyVisibility); got != "login" {
+		t.Errorf("expected fixed visibility, but got %q", got)
+	}
+	if diags = m.Validate(); len(diags) != 1 || diags[0].Key != meta.KeyURL {
+		t.Errorf("only URL diagnostic expected, but got %v", diags)
+	}
+}
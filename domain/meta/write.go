@@ -16,6 +16,8 @@ package meta
 import "io"
 
 // Write writes metadata to a writer, excluding computed and propery values.
+// If YamlSep is set, the metadata is written as YAML front matter, including
+// the separator lines.
 func (m *Meta) Write(w io.Writer) (int, error) {
 	return m.doWrite(w, IsComputed)
 }
@@ -27,6 +29,9 @@ func (m *Meta) WriteComputed(w io.Writer) (int, error) {
 }
 
 func (m *Meta) doWrite(w io.Writer, ignoreKeyPred func(string) bool) (length int, err error) {
+	if m.YamlSep {
+		return m.doWriteYAML(w, ignoreKeyPred)
+	}
 	for key, val := range m.Computed() {
 		if ignoreKeyPred(key) {
 			continue
@@ -19,6 +19,7 @@ import (
 
 	"t73f.de/r/zsc/domain/id"
 	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsx/input"
 )
 
 const testID = id.Zid(98765432101234)
@@ -57,3 +58,18 @@ func TestWriteMeta(t *testing.T) {
 	m.Set("auth", "basic")
 	assertWriteMeta(t, m, "title: TITLE\nauth: basic\nuser: zettel\n")
 }
+
+func TestWriteYAML(t *testing.T) {
+	t.Parallel()
+	m := newMeta("A: title", []string{"#t1", "#t2"}, "")
+	m.Set(meta.KeyPrecursor, "00010000000001")
+	m.Set("draft", "true")
+	m.YamlSep = true
+	exp := "---\ntitle: \"A: title\"\ntags:\n  - t1\n  - t2\ndraft: \"true\"\nprecursor:\n  - \"00010000000001\"\n---\n"
+	assertWriteMeta(t, m, exp)
+
+	got := meta.NewFromInput(testID, input.NewInput([]byte(exp)))
+	if !got.Equal(m, true) || !got.YamlSep {
+		t.Errorf("round trip failed: expected %v, but got %v", m.Map(), got.Map())
+	}
+}
@@ -0,0 +1,351 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package meta
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/zsx/input"
+)
+
+// YAML front matter is a subset of YAML: a top-level mapping of keys to
+// plain, quoted, or block scalars, or to flow or block sequences. Nested
+// mappings are not supported.
+//
+// Since values of Zettelstore metadata may start with '#', e.g. "tags: #a #b",
+// a '#' in a plain scalar does not start a comment.
+
+// yamlEnd searches the line that closes the YAML front matter, which starts
+// at the given position. It returns the start of this line and the position
+// after it. If there is no such line, ok is false. The search stops at the
+// first line that cannot be part of the front matter, e.g. at a line of prose.
+func yamlEnd(src []byte, pos int) (contentEnd, end int, ok bool) {
+	for pos < len(src) {
+		eol := bytes.IndexAny(src[pos:], "\r\n")
+		lineEnd := len(src)
+		if eol >= 0 {
+			lineEnd = pos + eol
+		}
+		line := src[pos:lineEnd]
+		if bytes.HasPrefix(line, []byte("---")) || bytes.Equal(bytes.TrimRight(line, " \t"), []byte("...")) {
+			end = lineEnd
+			if end < len(src) && src[end] == '\r' {
+				end++
+			}
+			if end < len(src) && src[end] == '\n' {
+				end++
+			}
+			return pos, end, true
+		}
+		if !isYAMLLine(string(line)) {
+			return 0, 0, false
+		}
+		pos = lineEnd
+		if pos < len(src) && src[pos] == '\r' {
+			pos++
+		}
+		if pos < len(src) && src[pos] == '\n' {
+			pos++
+		}
+	}
+	return 0, 0, false
+}
+
+// parseYAML parses the YAML front matter of the input, if it is closed by a
+// separator line. It returns false, if there is no closing line.
+func parseYAML(m *Meta, inp *input.Input) bool {
+	contentEnd, end, ok := yamlEnd(inp.Src, inp.Pos)
+	if !ok {
+		return false
+	}
+	lines := strings.FieldsFunc(string(inp.Src[inp.Pos:contentEnd]), func(r rune) bool { return r == '\n' || r == '\r' })
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		i++
+		if isYAMLSkip(line) || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, rest, found := strings.Cut(line, ":")
+		if !found || key == "" || strings.IndexFunc(key, func(r rune) bool { return !isHeader(r) }) >= 0 {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		var cont []string
+		for ; i < len(lines); i++ {
+			next := lines[i]
+			if strings.TrimSpace(next) == "" || next[0] == ' ' || next[0] == '\t' || (rest == "" && isYAMLItem(next)) {
+				cont = append(cont, next)
+				continue
+			}
+			break
+		}
+		addYAMLValue(m, key, rest, cont)
+	}
+	inp.SetPos(end)
+	m.YamlSep = true
+	return true
+}
+
+func isYAMLSkip(line string) bool {
+	s := strings.TrimSpace(line)
+	return s == "" || s[0] == '#' || s[0] == '%'
+}
+
+// isYAMLLine returns true, if the line may be part of the front matter: an
+// empty line, a comment, a key, an indented line, or an element of a sequence.
+func isYAMLLine(line string) bool {
+	if isYAMLSkip(line) || line[0] == ' ' || line[0] == '\t' || isYAMLItem(line) {
+		return true
+	}
+	key, _, found := strings.Cut(line, ":")
+	return found && key != "" && strings.IndexFunc(key, func(r rune) bool { return !isHeader(r) }) < 0
+}
+
+func isYAMLItem(line string) bool {
+	s := strings.TrimLeft(line, " \t")
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+func addYAMLValue(m *Meta, key, rest string, cont []string) {
+	if rest == "" {
+		for _, line := range cont {
+			if isYAMLSkip(line) {
+				continue
+			}
+			if isYAMLItem(line) {
+				addYAMLList(m, key, blockItems(cont))
+				return
+			}
+			break
+		}
+	}
+	var sb strings.Builder
+	if rest != "" && rest[0] != '|' && rest[0] != '>' {
+		sb.WriteString(rest)
+	}
+	for _, line := range cont {
+		if s := strings.TrimSpace(line); s != "" {
+			if sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(s)
+		}
+	}
+	val := sb.String()
+	if strings.HasPrefix(val, "[") {
+		addYAMLList(m, key, flowItems(val))
+		return
+	}
+	addToMeta(m, key, Value(yamlScalar(val)))
+}
+
+// blockItems returns the items of a block sequence.
+func blockItems(lines []string) []string {
+	var result []string
+	for _, line := range lines {
+		s := strings.TrimSpace(line)
+		if s == "" {
+			continue
+		}
+		if isYAMLItem(line) {
+			result = append(result, strings.TrimSpace(s[1:]))
+		} else if len(result) > 0 {
+			result[len(result)-1] += " " + s
+		}
+	}
+	for i, item := range result {
+		result[i] = yamlScalar(item)
+	}
+	return result
+}
+
+// flowItems returns the items of a flow sequence, like "[a, 'b', "c"]".
+func flowItems(s string) []string {
+	s = strings.TrimPrefix(s, "[")
+	if pos := strings.LastIndexByte(s, ']'); pos >= 0 {
+		s = s[:pos]
+	}
+	var result []string
+	start, quote := 0, byte(0)
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == ',':
+			result = append(result, yamlScalar(strings.TrimSpace(s[start:i])))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		result = append(result, yamlScalar(last))
+	}
+	return result
+}
+
+func addYAMLList(m *Meta, key string, items []string) {
+	isTagSet := Type(key) == TypeTagSet
+	var elems []string
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		if isTagSet {
+			item = string(Value(item).NormalizeTag())
+		}
+		elems = append(elems, item)
+	}
+	addToMeta(m, key, Value(strings.Join(elems, " ")))
+}
+
+// yamlScalar returns the value of a plain or a quoted scalar. A value is
+// only treated as quoted, if its closing quote is the last non-blank
+// character. Otherwise it is returned unchanged.
+func yamlScalar(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	var result string
+	var rest string
+	switch s[0] {
+	case '\'':
+		result, rest = unquoteSingle(s[1:])
+	case '"':
+		result, rest = unquoteDouble(s[1:])
+	default:
+		return s
+	}
+	if strings.TrimSpace(rest) != "" {
+		return s
+	}
+	return result
+}
+
+// unquoteSingle returns the content of a single quoted scalar and the text
+// after the closing quote. If there is no closing quote, rest is s itself.
+func unquoteSingle(s string) (result, rest string) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '\'' {
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else {
+				return sb.String(), s[i+1:]
+			}
+		}
+		sb.WriteByte(ch)
+	}
+	return "", s
+}
+
+// unquoteDouble returns the content of a double quoted scalar and the text
+// after the closing quote. If there is no closing quote, rest is s itself.
+func unquoteDouble(s string) (result, rest string) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '"' {
+			return sb.String(), s[i+1:]
+		}
+		if ch != '\\' || i+1 == len(s) {
+			sb.WriteByte(ch)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 't', 'r':
+			sb.WriteByte(' ') // metadata values are always on one line
+		case '0':
+		case 'x', 'u', 'U':
+			size := 2
+			if s[i] == 'u' {
+				size = 4
+			} else if s[i] == 'U' {
+				size = 8
+			}
+			if i+size < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32); err == nil {
+					sb.WriteRune(rune(r))
+					i += size
+					continue
+				}
+			}
+			sb.WriteByte(s[i])
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", s
+}
+
+func (m *Meta) doWriteYAML(w io.Writer, ignoreKeyPred func(string) bool) (int, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	for key, val := range m.Computed() {
+		if ignoreKeyPred(key) {
+			continue
+		}
+		buf.WriteString(key)
+		buf.WriteByte(':')
+		if ty := Type(key); ty.IsSet && val != "" {
+			for elem := range val.Fields() {
+				if ty == TypeTagSet {
+					elem = string(Value(elem).CleanTag())
+				}
+				buf.WriteString("\n  - ")
+				buf.WriteString(yamlQuote(elem))
+			}
+		} else {
+			buf.WriteByte(' ')
+			buf.WriteString(yamlQuote(string(val)))
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("---\n")
+	return w.Write(buf.Bytes())
+}
+
+// yamlQuote returns the string as a double quoted scalar, if it would not be
+// read as a plain string scalar by other YAML tools.
+func yamlQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` ") && !isYAMLNonString(s) &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #") &&
+		!strings.HasSuffix(s, ":") && !strings.HasSuffix(s, " ") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// isYAMLNonString returns true, if a plain scalar would be resolved to
+// something else than a string, like a number, a boolean, null, or a
+// timestamp. YAML 1.1 is more liberal than YAML 1.2, e.g. "yes" is a boolean
+// and "00010000000001" is an octal number. Therefore, every scalar that
+// starts like a number is treated as a non-string.
+func isYAMLNonString(s string) bool {
+	switch strings.ToLower(s) {
+	case "~", "null", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	ch := s[0]
+	return ('0' <= ch && ch <= '9') || ch == '+' || ch == '.'
+}
@@ -0,0 +1,246 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package policy evaluates the access rights of users to zettel, in the same
+// way as Zettelstore does. This allows to enforce the access rules of the
+// Zettelstore manual, e.g. for static exports.
+package policy
+
+import (
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/webapi"
+)
+
+// Config contains the configuration of a Zettelstore that is relevant for
+// evaluating access rights.
+type Config struct {
+	WithAuth          bool            // Authentication is enabled
+	ReadOnly          bool            // Zettelstore is in read-only mode
+	ExpertMode        bool            // Expert mode is enabled
+	Owner             id.Zid          // Zettel identifier of the owner
+	DefaultVisibility meta.Visibility // Used if a zettel has no visibility; "login" if not set
+}
+
+// Rights returns the access rights of the given user for the zettel with the
+// given metadata. User is the metadata of the user zettel, or nil if there is
+// no authenticated user.
+func (c *Config) Rights(user, m *meta.Meta) webapi.ZettelRights {
+	var result webapi.ZettelRights
+	if c.CanCreate(user, m) {
+		result |= webapi.ZettelCanCreate
+	}
+	if c.CanRead(user, m) {
+		result |= webapi.ZettelCanRead
+	}
+	if c.CanWrite(user, m, m) {
+		result |= webapi.ZettelCanWrite
+	}
+	if c.CanDelete(user, m) {
+		result |= webapi.ZettelCanDelete
+	}
+	if result == 0 {
+		return webapi.ZettelCanNone
+	}
+	return result
+}
+
+// UserRole returns the role of the given user. Without authentication, every
+// user acts as the owner.
+func (c *Config) UserRole(user *meta.Meta) meta.UserRole {
+	if user == nil {
+		if c.WithAuth {
+			return meta.UserRoleUnknown
+		}
+		return meta.UserRoleOwner
+	}
+	if c.isOwner(user) {
+		return meta.UserRoleOwner
+	}
+	if val, found := user.Get(meta.KeyUserRole); found {
+		if ur := val.AsUserRole(); ur != meta.UserRoleUnknown {
+			return ur
+		}
+	}
+	return meta.UserRoleReader
+}
+
+// CanCreate returns true, if the user is allowed to create a zettel with the
+// given metadata.
+func (c *Config) CanCreate(user, newMeta *meta.Meta) bool {
+	if newMeta == nil || c.ReadOnly {
+		return false
+	}
+	if !c.WithAuth {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+	return c.isOwner(user) || c.userCanCreate(user, newMeta)
+}
+
+// CanRead returns true, if the user is allowed to read the zettel.
+func (c *Config) CanRead(user, m *meta.Meta) bool {
+	if m == nil {
+		return false
+	}
+	vis := c.visibility(m)
+	if !c.WithAuth {
+		return vis != meta.VisibilityExpert || c.ExpertMode
+	}
+	if vis == meta.VisibilityExpert {
+		return c.isOwner(user) && c.ExpertMode
+	}
+	return c.isOwner(user) || c.userCanRead(user, m, vis)
+}
+
+// noChangeUser lists all keys of a user zettel that only the owner may change.
+var noChangeUser = []string{meta.KeyID, meta.KeyRole, meta.KeyUserID, meta.KeyUserRole}
+
+// CanWrite returns true, if the user is allowed to update the zettel with
+// the old metadata to the new metadata.
+func (c *Config) CanWrite(user, oldMeta, newMeta *meta.Meta) bool {
+	if oldMeta == nil || newMeta == nil || oldMeta.Zid != newMeta.Zid || !c.canChange(user, oldMeta) {
+		return false
+	}
+	vis := c.visibility(oldMeta)
+	if !c.WithAuth {
+		return vis != meta.VisibilityExpert || c.ExpertMode
+	}
+	if user == nil {
+		return false
+	}
+	if vis == meta.VisibilityExpert {
+		return c.isOwner(user) && c.ExpertMode
+	}
+	if c.isOwner(user) {
+		return true
+	}
+	if !c.userCanRead(user, oldMeta, vis) {
+		return false
+	}
+	if _, isUser := oldMeta.Get(meta.KeyUserID); isUser {
+		// The user may change its own zettel, but not its identity or role.
+		for _, key := range noChangeUser {
+			if oldMeta.GetDefault(key, "") != newMeta.GetDefault(key, "") {
+				return false
+			}
+		}
+		return true
+	}
+	switch c.UserRole(user) {
+	case meta.UserRoleReader, meta.UserRoleCreator:
+		return false
+	}
+	return c.userCanCreate(user, newMeta)
+}
+
+// CanDelete returns true, if the user is allowed to delete the zettel.
+func (c *Config) CanDelete(user, m *meta.Meta) bool {
+	if m == nil || !c.canChange(user, m) {
+		return false
+	}
+	vis := c.visibility(m)
+	if !c.WithAuth {
+		return vis != meta.VisibilityExpert || c.ExpertMode
+	}
+	if user == nil {
+		return false
+	}
+	if vis == meta.VisibilityExpert {
+		return c.isOwner(user) && c.ExpertMode
+	}
+	return c.isOwner(user)
+}
+
+// isOwner returns true, if the user is the configured owner, or if its user
+// zettel has the user role "owner".
+func (c *Config) isOwner(user *meta.Meta) bool {
+	if user == nil {
+		return false
+	}
+	if c.Owner != id.Invalid && user.Zid == c.Owner {
+		return true
+	}
+	val, found := user.Get(meta.KeyUserRole)
+	return found && val.AsUserRole() == meta.UserRoleOwner
+}
+
+func (c *Config) visibility(m *meta.Meta) meta.Visibility {
+	if val, found := m.Get(meta.KeyVisibility); found {
+		if vis := val.AsVisibility(); vis != meta.VisibilityUnknown {
+			return vis
+		}
+	}
+	if c.DefaultVisibility != 0 {
+		return c.DefaultVisibility
+	}
+	return meta.VisibilityLogin
+}
+
+func (c *Config) userCanCreate(user, newMeta *meta.Meta) bool {
+	if c.UserRole(user) == meta.UserRoleReader {
+		return false
+	}
+	// Only the owner is allowed to create user zettel.
+	_, isUser := newMeta.Get(meta.KeyUserID)
+	return !isUser
+}
+
+func (c *Config) userCanRead(user, m *meta.Meta, vis meta.Visibility) bool {
+	switch vis {
+	case meta.VisibilityOwner, meta.VisibilityExpert:
+		return false
+	case meta.VisibilityPublic:
+		return true
+	}
+	if user == nil {
+		return false
+	}
+	if _, isUser := m.Get(meta.KeyUserID); isUser {
+		// Only the user can read its own zettel.
+		return user.Zid == m.Zid
+	}
+	switch c.UserRole(user) {
+	case meta.UserRoleReader, meta.UserRoleWriter, meta.UserRoleOwner:
+		return true
+	case meta.UserRoleCreator:
+		return vis == meta.VisibilityCreator
+	}
+	return false
+}
+
+// canChange checks the "read-only" key of the zettel and the read-only mode.
+func (c *Config) canChange(user, m *meta.Meta) bool {
+	if c.ReadOnly {
+		return false
+	}
+	ro, found := m.Get(meta.KeyReadOnly)
+	if !found {
+		return true
+	}
+	if user == nil && !c.WithAuth {
+		// Without authentication, every user acts as an owner.
+		return ro != meta.ValueUserRoleOwner && !ro.AsBool()
+	}
+	switch userRole := c.UserRole(user); ro {
+	case meta.ValueUserRoleReader:
+		return userRole > meta.UserRoleReader
+	case meta.ValueUserRoleWriter:
+		return userRole > meta.UserRoleWriter
+	case meta.ValueUserRoleOwner:
+		return userRole > meta.UserRoleOwner
+	}
+	return !ro.AsBool()
+}
@@ -0,0 +1,122 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package policy_test
+
+import (
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/domain/policy"
+	"t73f.de/r/zsc/webapi"
+)
+
+const (
+	none   = webapi.ZettelCanNone
+	create = webapi.ZettelCanCreate
+	read   = webapi.ZettelCanRead
+	write  = webapi.ZettelCanWrite
+	del    = webapi.ZettelCanDelete
+	all    = create | read | write | del
+)
+
+func newUser(zid id.Zid, role string) *meta.Meta {
+	m := meta.New(zid)
+	m.Set(meta.KeyUserID, meta.Value("user"+zid.String()))
+	if role != "" {
+		m.Set(meta.KeyUserRole, meta.Value(role))
+	}
+	return m
+}
+
+func newZettel(zid id.Zid, keyVals ...string) *meta.Meta {
+	m := meta.New(zid)
+	for i := 0; i+1 < len(keyVals); i += 2 {
+		m.Set(keyVals[i], meta.Value(keyVals[i+1]))
+	}
+	return m
+}
+
+func TestRights(t *testing.T) {
+	t.Parallel()
+	owner := newUser(1, "")
+	writer := newUser(2, meta.ValueUserRoleWriter)
+	reader := newUser(3, meta.ValueUserRoleReader)
+	creator := newUser(4, meta.ValueUserRoleCreator)
+	roleOwner := newUser(5, meta.ValueUserRoleOwner)
+	auth := &policy.Config{WithAuth: true, Owner: 1}
+
+	testCases := []struct {
+		cfg  *policy.Config
+		user *meta.Meta
+		m    *meta.Meta
+		exp  webapi.ZettelRights
+	}{
+		// Without authentication
+		{&policy.Config{}, nil, newZettel(10), all},
+		{&policy.Config{}, nil, newZettel(10, meta.KeyReadOnly, "true"), create | read},
+		{&policy.Config{}, nil, newZettel(10, meta.KeyVisibility, "expert"), create},
+		{&policy.Config{ExpertMode: true}, nil, newZettel(10, meta.KeyVisibility, "expert"), all},
+		{&policy.Config{ReadOnly: true}, nil, newZettel(10), read},
+
+		// With authentication
+		{auth, nil, newZettel(10), none},
+		{auth, nil, newZettel(10, meta.KeyVisibility, "public"), read},
+		{auth, owner, newZettel(10), all},
+		{auth, owner, newZettel(10, meta.KeyVisibility, "expert"), create},
+		{&policy.Config{WithAuth: true, Owner: 1, ExpertMode: true}, owner, newZettel(10, meta.KeyVisibility, "expert"), all},
+		{auth, roleOwner, newZettel(10), all},
+		{auth, roleOwner, newZettel(10, meta.KeyVisibility, "owner"), all},
+		{auth, roleOwner, newZettel(10, meta.KeyVisibility, "expert"), create},
+		{auth, roleOwner, writer, all},
+		{auth, writer, newZettel(10), create | read | write},
+		{auth, writer, newZettel(10, meta.KeyReadOnly, meta.ValueUserRoleWriter), create | read},
+		{auth, writer, newZettel(10, meta.KeyReadOnly, meta.ValueUserRoleReader), create | read | write},
+		{auth, writer, newZettel(10, meta.KeyVisibility, "owner"), create},
+		{auth, writer, newZettel(10, meta.KeyUserID, "x"), none},
+		{auth, reader, newZettel(10), read},
+		{auth, reader, newZettel(10, meta.KeyVisibility, "public"), read},
+		{auth, creator, newZettel(10), create},
+		{auth, creator, newZettel(10, meta.KeyVisibility, "creator"), create | read},
+		{&policy.Config{WithAuth: true, Owner: 1, DefaultVisibility: meta.VisibilityPublic}, nil, newZettel(10), read},
+
+		// User zettel
+		{auth, writer, writer, read | write},
+		{auth, reader, reader, read | write},
+		{auth, reader, writer, none},
+	}
+	for i, tc := range testCases {
+		if got := tc.cfg.Rights(tc.user, tc.m); got != tc.exp {
+			t.Errorf("%d: expected rights %v, but got %v", i, tc.exp, got)
+		}
+	}
+}
+
+func TestCanWriteUser(t *testing.T) {
+	t.Parallel()
+	cfg := &policy.Config{WithAuth: true, Owner: 1}
+	user := newUser(2, meta.ValueUserRoleReader)
+	changed := user.Clone()
+	changed.Set(meta.KeyTitle, "New title")
+	if !cfg.CanWrite(user, user, changed) {
+		t.Error("user must be allowed to change own title")
+	}
+	changed.Set(meta.KeyUserRole, meta.ValueUserRoleOwner)
+	if cfg.CanWrite(user, user, changed) {
+		t.Error("user must not be allowed to change own role")
+	}
+	if !cfg.CanWrite(newUser(1, ""), user, changed) {
+		t.Error("owner must be allowed to change user role")
+	}
+}
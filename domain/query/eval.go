@@ -0,0 +1,219 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package query
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/zsc/domain/id/idset"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/webapi"
+)
+
+// Select returns all metadata that are selected by the query, in the order
+// specified by the query. Without an order, the metadata are sorted by
+// descending zettel identifier.
+func (q *Query) Select(metas []*meta.Meta) []*meta.Meta {
+	var result []*meta.Meta
+	for _, m := range metas {
+		if q.matches(m) {
+			result = append(result, m)
+		}
+	}
+
+	var rnd *rand.Rand
+	if q.random || q.pick > 0 {
+		seed := uint64(q.Seed)
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		rnd = rand.New(rand.NewPCG(seed, seed))
+
+		// Same seed must result in same order, independent of the input order.
+		slices.SortFunc(result, func(a, b *meta.Meta) int { return cmp.Compare(a.Zid, b.Zid) })
+	}
+	if q.pick > 0 && q.pick < len(result) {
+		rnd.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
+		result = result[:q.pick]
+	}
+	if q.random && len(q.order) == 0 {
+		rnd.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
+	} else {
+		slices.SortStableFunc(result, q.compare)
+	}
+
+	if q.offset > 0 {
+		if q.offset >= len(result) {
+			return nil
+		}
+		result = result[q.offset:]
+	}
+	if q.limit > 0 && q.limit < len(result) {
+		result = result[:q.limit]
+	}
+	return result
+}
+
+// Set returns the identifier of all metadata that are selected by the query.
+func (q *Query) Set(metas []*meta.Meta) *idset.Set {
+	result := idset.New()
+	for _, m := range q.Select(metas) {
+		result.Add(m.Zid)
+	}
+	return result
+}
+
+func (q *Query) matches(m *meta.Meta) bool {
+	if len(q.zids) > 0 && !slices.Contains(q.zids, m.Zid) {
+		return false
+	}
+	for _, group := range q.groups {
+		if matchGroup(group, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGroup(group []term, m *meta.Meta) bool {
+	for _, t := range group {
+		if t.matches(m) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true, if the term without its negation matches the metadata.
+func (t term) matches(m *meta.Meta) bool {
+	if t.key == "" {
+		for key, val := range m.All() {
+			if matchValue(meta.Type(key), val, t.op, t.value) {
+				return true
+			}
+		}
+		return false
+	}
+	val, found := m.Get(t.key)
+	if t.op == webapi.ExistOperator || t.value == "" {
+		return found
+	}
+	return found && matchValue(meta.Type(t.key), val, t.op, t.value)
+}
+
+func matchValue(ty *meta.DescriptionType, val meta.Value, op, s string) bool {
+	if !ty.IsSet {
+		return matchScalar(ty, string(val), op, s)
+	}
+	elemType := meta.TypeWord
+	if ty == meta.TypeIDSet {
+		elemType = meta.TypeID
+	}
+	if ty == meta.TypeTagSet {
+		s = string(meta.Value(s).CleanTag())
+	}
+	for elem := range val.Elems() {
+		if ty == meta.TypeTagSet {
+			elem = elem.CleanTag()
+		}
+		if matchScalar(elemType, string(elem), op, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchScalar(ty *meta.DescriptionType, val, op, s string) bool {
+	val, s = strings.ToLower(val), strings.ToLower(s)
+	switch op {
+	case webapi.SearchOperatorEqual:
+		return compareScalar(ty, val, s) == 0
+	case webapi.SearchOperatorHas:
+		switch ty {
+		case meta.TypeTimestamp:
+			return strings.HasPrefix(val, s)
+		case meta.TypeEmpty, meta.TypeString:
+			return strings.Contains(val, s)
+		}
+		return compareScalar(ty, val, s) == 0
+	case webapi.SearchOperatorPrefix:
+		return strings.HasPrefix(val, s)
+	case webapi.SearchOperatorSuffix:
+		return strings.HasSuffix(val, s)
+	case webapi.SearchOperatorMatch:
+		return strings.Contains(val, s)
+	case webapi.SearchOperatorLess:
+		return compareScalar(ty, val, s) < 0
+	case webapi.SearchOperatorGreater:
+		return compareScalar(ty, val, s) > 0
+	}
+	return false
+}
+
+// compareScalar compares two values according to their type.
+func compareScalar(ty *meta.DescriptionType, a, b string) int {
+	switch ty {
+	case meta.TypeNumber, meta.TypeID:
+		na, errA := strconv.ParseInt(a, 10, 64)
+		nb, errB := strconv.ParseInt(b, 10, 64)
+		if errA == nil && errB == nil {
+			return cmp.Compare(na, nb)
+		}
+	case meta.TypeTimestamp:
+		return strings.Compare(meta.ExpandTimestamp(meta.Value(a)), meta.ExpandTimestamp(meta.Value(b)))
+	}
+	return strings.Compare(a, b)
+}
+
+// compare orders two metadata according to the order specification of the
+// query. Missing values are always sorted last.
+func (q *Query) compare(a, b *meta.Meta) int {
+	for _, spec := range q.order {
+		if spec.key == meta.KeyID {
+			if c := cmp.Compare(a.Zid, b.Zid); c != 0 {
+				return reverse(c, spec.reverse)
+			}
+			continue
+		}
+		valA, foundA := a.Get(spec.key)
+		valB, foundB := b.Get(spec.key)
+		switch {
+		case !foundA && !foundB:
+			continue
+		case !foundA:
+			return 1
+		case !foundB:
+			return -1
+		}
+		ty := meta.Type(spec.key)
+		if ty.IsSet {
+			ty = meta.TypeWord
+		}
+		if c := compareScalar(ty, strings.ToLower(string(valA)), strings.ToLower(string(valB))); c != 0 {
+			return reverse(c, spec.reverse)
+		}
+	}
+	return cmp.Compare(b.Zid, a.Zid)
+}
+
+func reverse(c int, rev bool) int {
+	if rev {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,194 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package query evaluates Zettelstore query expressions against a local
+// collection of metadata.
+//
+// Since only metadata is available, search terms without a key are matched
+// against all metadata values, but not against the zettel content. Directives
+// that need the zettel content or the links between zettel, e.g. CONTEXT or
+// UNLINKED, are not supported. Actions, i.e. everything after "|", are ignored.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/webapi"
+)
+
+// Query is a parsed query expression.
+type Query struct {
+	zids   []id.Zid
+	groups [][]term // terms of a group must all match, one group must match
+	order  []orderSpec
+	random bool
+	pick   int
+	offset int
+	limit  int
+
+	// Seed initializes the random number generator for PICK and RANDOM.
+	// Typically, it is the value of URL parameter [webapi.QueryKeySeed].
+	// If it is zero, a random seed is used.
+	Seed int
+}
+
+type term struct {
+	key    string // empty, if all metadata values should be searched
+	op     string // operator without negation
+	negate bool
+	value  string
+}
+
+type orderSpec struct {
+	key     string
+	reverse bool
+}
+
+// Parse parses the string as a query expression.
+func Parse(s string) (*Query, error) {
+	q := &Query{}
+	words := strings.Fields(s)
+	pos := 0
+	for ; pos < len(words); pos++ {
+		zid, err := id.Parse(words[pos])
+		if err != nil {
+			break
+		}
+		q.zids = append(q.zids, zid)
+	}
+	if pos < len(words) {
+		switch words[pos] {
+		case webapi.ContextDirective, webapi.FolgeDirective, webapi.IdentDirective,
+			webapi.ItemsDirective, webapi.SequelDirective, webapi.ThreadDirective,
+			webapi.UnlinkedDirective:
+			return nil, fmt.Errorf("directive %s not supported", words[pos])
+		}
+	}
+
+	var group []term
+	for ; pos < len(words); pos++ {
+		word := words[pos]
+		if strings.HasPrefix(word, webapi.ActionSeparator) {
+			break
+		}
+		switch word {
+		case webapi.OrDirective:
+			q.groups = append(q.groups, group)
+			group = nil
+			continue
+		case webapi.RandomDirective:
+			q.random = true
+			continue
+		case webapi.OrderDirective:
+			if spec, next, ok := parseOrder(words, pos+1); ok {
+				q.order = append(q.order, spec)
+				pos = next
+				continue
+			}
+		case webapi.PickDirective, webapi.OffsetDirective, webapi.LimitDirective:
+			if pos+1 < len(words) {
+				if n, err := strconv.Atoi(words[pos+1]); err == nil && n >= 0 {
+					q.setNumber(word, n)
+					pos++
+					continue
+				}
+			}
+		}
+		group = append(group, parseTerm(word))
+	}
+	q.groups = append(q.groups, group)
+	return q, nil
+}
+
+func parseOrder(words []string, pos int) (orderSpec, int, bool) {
+	var spec orderSpec
+	if pos < len(words) && words[pos] == webapi.ReverseDirective {
+		spec.reverse = true
+		pos++
+	}
+	if pos < len(words) && isKey(words[pos]) {
+		spec.key = words[pos]
+		return spec, pos, true
+	}
+	return spec, 0, false
+}
+
+func (q *Query) setNumber(directive string, n int) {
+	switch directive {
+	case webapi.PickDirective:
+		if q.pick == 0 || n < q.pick {
+			q.pick = n
+		}
+	case webapi.OffsetDirective:
+		q.offset = n
+	case webapi.LimitDirective:
+		if q.limit == 0 || n < q.limit {
+			q.limit = n
+		}
+	}
+}
+
+// operators lists all operators without negation.
+var operators = []string{
+	webapi.ExistOperator,
+	webapi.SearchOperatorEqual,
+	webapi.SearchOperatorHas,
+	webapi.SearchOperatorPrefix,
+	webapi.SearchOperatorSuffix,
+	webapi.SearchOperatorMatch,
+	webapi.SearchOperatorLess,
+	webapi.SearchOperatorGreater,
+}
+
+func parseTerm(word string) term {
+	keyLen := 0
+	for keyLen < len(word) && isKeyByte(word[keyLen]) {
+		keyLen++
+	}
+	if keyLen > 0 {
+		if op, negate, rest, ok := parseOperator(word[keyLen:]); ok {
+			return term{key: word[:keyLen], op: op, negate: negate, value: rest}
+		}
+	}
+	if op, negate, rest, ok := parseOperator(word); ok && op != webapi.ExistOperator {
+		return term{op: op, negate: negate, value: rest}
+	}
+	if value, found := strings.CutPrefix(word, webapi.SearchOperatorNot); found {
+		return term{op: webapi.SearchOperatorMatch, negate: true, value: value}
+	}
+	return term{op: webapi.SearchOperatorMatch, value: word}
+}
+
+func parseOperator(s string) (op string, negate bool, rest string, ok bool) {
+	s, negate = strings.CutPrefix(s, webapi.SearchOperatorNot)
+	for _, op = range operators {
+		if rest, ok = strings.CutPrefix(s, op); ok {
+			return op, negate, rest, true
+		}
+	}
+	return "", false, "", false
+}
+
+func isKey(s string) bool {
+	for i := range len(s) {
+		if !isKeyByte(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isKeyByte(b byte) bool { return ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || b == '-' }
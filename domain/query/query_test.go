@@ -0,0 +1,128 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package query_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/domain/query"
+)
+
+func testMetas() []*meta.Meta {
+	return []*meta.Meta{
+		meta.NewWithData(1, map[string]string{
+			meta.KeyTitle: "First Zettel", meta.KeyTags: "#a #b", meta.KeyCreated: "20250101120000", "my-number": "5",
+		}),
+		meta.NewWithData(2, map[string]string{
+			meta.KeyTitle: "Second", meta.KeyTags: "#b", meta.KeyRole: "note", meta.KeyCreated: "20260301120000", "my-number": "17",
+		}),
+		meta.NewWithData(3, map[string]string{
+			meta.KeyTitle: "Third zettel", meta.KeyRole: "zettel", meta.KeyPrecursor: "00000000000001", "my-number": "-3",
+		}),
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		query string
+		exp   []id.Zid
+	}{
+		{"", []id.Zid{3, 2, 1}},
+		{"zettel", []id.Zid{3, 1}},
+		{"!zettel", []id.Zid{2}},
+		{"tags:a", []id.Zid{1}},
+		{"tags:#b", []id.Zid{2, 1}},
+		{"tags!:#a", []id.Zid{3, 2}},
+		{"tags=b", []id.Zid{2, 1}},
+		{"title=second", []id.Zid{2}},
+		{"title:zett", []id.Zid{3, 1}},
+		{"title[sec", []id.Zid{2}},
+		{"title]zettel", []id.Zid{3, 1}},
+		{"title!]zettel", []id.Zid{2}},
+		{"title~ird", []id.Zid{3}},
+		{"role?", []id.Zid{3, 2}},
+		{"role!?", []id.Zid{1}},
+		{"role:", []id.Zid{3, 2}},
+		{"created:2026", []id.Zid{2}},
+		{"created<2026", []id.Zid{1}},
+		{"created!<2026", []id.Zid{3, 2}},
+		{"my-number>4", []id.Zid{2, 1}},
+		{"my-number<10", []id.Zid{3, 1}},
+		{"precursor:00000000000001", []id.Zid{3}},
+		{"tags:a OR role:note", []id.Zid{2, 1}},
+		{"00000000000001 00000000000003", []id.Zid{3, 1}},
+		{"ORDER title", []id.Zid{1, 2, 3}},
+		{"ORDER REVERSE my-number", []id.Zid{2, 1, 3}},
+		{"ORDER role", []id.Zid{2, 3, 1}},
+		{"ORDER id", []id.Zid{1, 2, 3}},
+		{"OFFSET 1", []id.Zid{2, 1}},
+		{"LIMIT 2", []id.Zid{3, 2}},
+		{"OFFSET 1 LIMIT 1", []id.Zid{2}},
+		{"OFFSET 5", nil},
+		{"LIMIT", nil},
+		{"tags:b | KEYS", []id.Zid{2, 1}},
+	}
+	metas := testMetas()
+	for i, tc := range testCases {
+		q, err := query.Parse(tc.query)
+		if err != nil {
+			t.Errorf("%d: %q: unexpected error %v", i, tc.query, err)
+			continue
+		}
+		var got []id.Zid
+		for _, m := range q.Select(metas) {
+			got = append(got, m.Zid)
+		}
+		if !slices.Equal(got, tc.exp) {
+			t.Errorf("%d: %q: expected %v, but got %v", i, tc.query, tc.exp, got)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+	metas := testMetas()
+	for _, s := range []string{"RANDOM", "PICK 2"} {
+		q, err := query.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Seed = 4711
+		first := q.Set(metas).String()
+		reversed := slices.Clone(metas)
+		slices.Reverse(reversed)
+		if got := q.Set(reversed).String(); got != first {
+			t.Errorf("%q: same seed must give same result, but got %v and %v", s, first, got)
+		}
+		if exp := len(metas); s == "RANDOM" && len(q.Select(metas)) != exp {
+			t.Errorf("%q: expected %d elements", s, exp)
+		}
+		if s == "PICK 2" && q.Set(metas).Length() != 2 {
+			t.Errorf("%q: expected 2 elements, but got %v", s, first)
+		}
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	t.Parallel()
+	for i, s := range []string{"00000000000001 CONTEXT", "UNLINKED", "ITEMS"} {
+		if _, err := query.Parse(s); err == nil {
+			t.Errorf("%d: %q: expected an error", i, s)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package relation computes metadata that relates zettel to each other, like
+// inverse keys and references, for a local collection of zettel.
+package relation
+
+import (
+	"iter"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+// Compute sets all inverse keys (e.g. "folge" for "precursor"), together with
+// the keys "forward", "dead", "backward", and "back" for the given metadata.
+// Previous values of these keys are replaced.
+//
+// Refs maps a zettel identifier to the set of zettel referenced in the zettel
+// content, as returned by [t73f.de/r/zsc/sz.ZettelReferences]. Only zettel
+// contained in the given list are considered to be existing: references to
+// other zettel are stored in key "dead".
+func Compute(metas []*meta.Meta, refs map[id.Zid]*idset.Set) {
+	byZid := make(map[id.Zid]*meta.Meta, len(metas))
+	for _, m := range metas {
+		byZid[m.Zid] = m
+	}
+
+	var invKeys []*meta.DescriptionKey
+	for _, kd := range meta.GetSortedKeyDescriptions() {
+		if kd.Inverse != "" {
+			invKeys = append(invKeys, kd)
+		}
+	}
+	for _, m := range metas {
+		for _, kd := range invKeys {
+			m.Delete(kd.Inverse)
+		}
+		for _, key := range []string{meta.KeyBack, meta.KeyBackward, meta.KeyForward, meta.KeyDead} {
+			m.Delete(key)
+		}
+	}
+
+	inverse := make(map[id.Zid]map[string]*idset.Set)
+	backward := make(map[id.Zid]*idset.Set)
+	forward := make(map[id.Zid]*idset.Set, len(metas))
+	for _, m := range metas {
+		for key, val := range m.Rest() {
+			if inv := meta.Inverse(key); inv != "" {
+				for zid := range referencedZids(val) {
+					if _, found := byZid[zid]; found {
+						if inverse[zid] == nil {
+							inverse[zid] = make(map[string]*idset.Set)
+						}
+						inverse[zid][inv] = inverse[zid][inv].Add(m.Zid)
+					}
+				}
+			} else if t := meta.Type(key); (t == meta.TypeID || t == meta.TypeIDSet) && !meta.IsComputed(key) {
+				for zid := range referencedZids(val) {
+					if _, found := byZid[zid]; found && zid != m.Zid {
+						backward[zid] = backward[zid].Add(m.Zid)
+					}
+				}
+			}
+		}
+
+		var fwd, dead *idset.Set
+		refs[m.Zid].ForEach(func(zid id.Zid) {
+			if _, found := byZid[zid]; !found {
+				dead = dead.Add(zid)
+				return
+			}
+			fwd = fwd.Add(zid)
+			if zid != m.Zid {
+				backward[zid] = backward[zid].Add(m.Zid)
+			}
+		})
+		forward[m.Zid] = fwd
+		m.SetNonEmpty(meta.KeyForward, fwd.MetaValue())
+		m.SetNonEmpty(meta.KeyDead, dead.MetaValue())
+	}
+
+	for _, m := range metas {
+		for key, zids := range inverse[m.Zid] {
+			m.SetNonEmpty(key, zids.MetaValue())
+		}
+		bwd := backward[m.Zid]
+		m.SetNonEmpty(meta.KeyBackward, bwd.MetaValue())
+		back := bwd.Clone()
+		back.ISubstract(forward[m.Zid])
+		m.SetNonEmpty(meta.KeyBack, back.MetaValue())
+	}
+}
+
+func referencedZids(val meta.Value) iter.Seq[id.Zid] {
+	return func(yield func(id.Zid) bool) {
+		for s := range val.Fields() {
+			if zid, err := id.Parse(s); err == nil && !yield(zid) {
+				return
+			}
+		}
+	}
+}
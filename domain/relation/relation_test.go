@@ -0,0 +1,67 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package relation_test
+
+import (
+	"testing"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/domain/relation"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+	m1 := meta.New(10000000001)
+	m1.Set(meta.KeyFolge, "00010000000009") // stale value must be removed
+	m2 := meta.New(10000000002)
+	m2.Set(meta.KeyPrecursor, "00010000000001")
+	m2.Set(meta.KeySuperordinate, "00010000000001 00010000000009")
+	m3 := meta.New(10000000003)
+	m3.Set(meta.KeyPrecursor, "00010000000001")
+	m3.Set(meta.KeyPrequel, "00010000000002")
+	m3.Set("my-ref", "00010000000002")
+	metas := []*meta.Meta{m1, m2, m3}
+	refs := map[id.Zid]*idset.Set{
+		10000000001: idset.New(10000000002, 10000000008),
+		10000000002: idset.New(10000000001, 10000000002),
+	}
+	relation.Compute(metas, refs)
+
+	testCases := []struct {
+		m   *meta.Meta
+		key string
+		exp meta.Value
+	}{
+		{m1, meta.KeyFolge, "00010000000002 00010000000003"},
+		{m1, meta.KeySubordinate, "00010000000002"},
+		{m1, meta.KeyForward, "00010000000002"},
+		{m1, meta.KeyDead, "00010000000008"},
+		{m1, meta.KeyBackward, "00010000000002"},
+		{m1, meta.KeyBack, ""},
+		{m2, meta.KeySequel, "00010000000003"},
+		{m2, meta.KeyForward, "00010000000001 00010000000002"},
+		{m2, meta.KeyBackward, "00010000000001 00010000000003"},
+		{m2, meta.KeyBack, "00010000000003"},
+		{m2, meta.KeyFolge, ""},
+		{m3, meta.KeyBackward, ""},
+		{m3, meta.KeyForward, ""},
+	}
+	for i, tc := range testCases {
+		if got, _ := tc.m.Get(tc.key); got != tc.exp {
+			t.Errorf("%d: %v/%s: expected %q, but got %q", i, tc.m.Zid, tc.key, tc.exp, got)
+		}
+	}
+}
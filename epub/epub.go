@@ -0,0 +1,195 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package epub builds EPUB 3 publications from a collection of zettel.
+//
+// Every zettel becomes a chapter. Links between zettel of the same book are
+// rewritten to point to the respective chapter, and image BLOBs are stored
+// as separate files within the publication.
+package epub
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/webapi"
+)
+
+// Book is an EPUB publication under construction.
+type Book struct {
+	Identifier string    // Unique identifier; derived from the chapters if empty
+	Title      string    // Title of the book
+	Language   string    // Language of the book, "en" if empty
+	Author     string    // Optional author / creator of the book
+	Modified   time.Time // Modification time; current time if zero
+
+	chapters []chapter
+}
+
+type chapter struct {
+	zid     id.Zid
+	title   string
+	lang    string
+	content *sx.Pair
+}
+
+// NewBook creates a new book with the given title.
+func NewBook(title string) *Book {
+	return &Book{Title: title}
+}
+
+// Add a zettel as a new chapter. The content should be the evaluated sz
+// encoding of the zettel content.
+func (b *Book) Add(zid id.Zid, m webapi.ZettelMeta, content *sx.Pair) {
+	title := m[meta.KeyTitle]
+	if title == "" {
+		title = zid.String()
+	}
+	b.chapters = append(b.chapters, chapter{
+		zid:     zid,
+		title:   title,
+		lang:    m[meta.KeyLang],
+		content: content,
+	})
+}
+
+// Source retrieves zettel for a book. It is implemented by
+// [t73f.de/r/zsc/client.Client].
+type Source interface {
+	QueryZettel(ctx context.Context, query string) ([][]byte, error)
+	GetMetaData(ctx context.Context, zid id.Zid) (webapi.MetaRights, error)
+	GetEvaluatedSz(ctx context.Context, zid id.Zid, part string) (sx.Object, error)
+}
+
+// FetchQuery adds all zettel selected by the query, in the order of the
+// query result.
+func (b *Book) FetchQuery(ctx context.Context, src Source, query string) error {
+	lines, err := src.QueryZettel(ctx, query)
+	if err != nil {
+		return err
+	}
+	zids := make([]id.Zid, 0, len(lines))
+	for _, line := range lines {
+		if len(line) < id.LengthZid {
+			return fmt.Errorf("invalid query result line: %q", line)
+		}
+		zid, err2 := id.Parse(string(line[:id.LengthZid]))
+		if err2 != nil {
+			return err2
+		}
+		zids = append(zids, zid)
+	}
+	return b.Fetch(ctx, src, zids...)
+}
+
+// Fetch adds the given zettel in the given order, e.g. the zettel of a
+// "folge" sequence.
+func (b *Book) Fetch(ctx context.Context, src Source, zids ...id.Zid) error {
+	for _, zid := range zids {
+		mr, err := src.GetMetaData(ctx, zid)
+		if err != nil {
+			return err
+		}
+		obj, err := src.GetEvaluatedSz(ctx, zid, webapi.PartContent)
+		if err != nil {
+			return err
+		}
+		content, isPair := sx.GetPair(obj)
+		if !isPair {
+			return fmt.Errorf("content of zettel %v is not a list: %v", zid, obj)
+		}
+		b.Add(zid, mr.Meta, content)
+	}
+	return nil
+}
+
+// Write the book as an EPUB container.
+func (b *Book) Write(w io.Writer) error {
+	lang := b.Language
+	if lang == "" {
+		lang = meta.ValueLangEN
+	}
+	modified := b.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+
+	rd := newRenderer(b.chapters)
+	docs := make([][]byte, len(b.chapters))
+	for i, ch := range b.chapters {
+		doc, err := rd.renderChapter(ch, lang)
+		if err != nil {
+			return fmt.Errorf("zettel %v: %w", ch.zid, err)
+		}
+		docs[i] = doc
+	}
+
+	zw := zip.NewWriter(w)
+	// The mimetype must be the first file and must not be compressed.
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(fw, mimeType); err != nil {
+		return err
+	}
+	if err = writeFile(zw, "META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+	if err = writeFile(zw, packageDir+"package.opf", b.packageDocument(lang, modified.UTC(), rd.images)); err != nil {
+		return err
+	}
+	if err = writeFile(zw, packageDir+navFile, b.navDocument(lang)); err != nil {
+		return err
+	}
+	for i, ch := range b.chapters {
+		if err = writeFile(zw, packageDir+chapterFile(ch.zid), docs[i]); err != nil {
+			return err
+		}
+	}
+	for _, img := range rd.images {
+		if err = writeFile(zw, packageDir+img.name, img.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeFile(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err == nil {
+		_, err = fw.Write(data)
+	}
+	return err
+}
+
+func (b *Book) identifier() string {
+	if b.Identifier != "" {
+		return b.Identifier
+	}
+	h := sha256.New()
+	for _, ch := range b.chapters {
+		_, _ = io.WriteString(h, ch.zid.String())
+	}
+	return "urn:zettelstore:" + hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func chapterFile(zid id.Zid) string { return zid.String() + ".xhtml" }
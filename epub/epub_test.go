@@ -0,0 +1,148 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package epub_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/epub"
+	"t73f.de/r/zsc/webapi"
+)
+
+type testSource map[id.Zid]string
+
+func (ts testSource) QueryZettel(context.Context, string) ([][]byte, error) {
+	return [][]byte{[]byte("00010000000001 One"), []byte("00010000000002 Two")}, nil
+}
+
+func (ts testSource) GetMetaData(_ context.Context, zid id.Zid) (webapi.MetaRights, error) {
+	if _, found := ts[zid]; !found {
+		return webapi.MetaRights{}, errors.New("not found")
+	}
+	return webapi.MetaRights{Meta: webapi.ZettelMeta{"title": "Title " + zid.String()}}, nil
+}
+
+func (ts testSource) GetEvaluatedSz(_ context.Context, zid id.Zid, _ string) (sx.Object, error) {
+	return sxreader.MakeReader(strings.NewReader(ts[zid])).Read()
+}
+
+var testZettel = testSource{
+	10000000001: `(BLOCK (PARA (TEXT "See ") (LINK () (FOUND "00010000000002#sec") (TEXT "two")) (TEXT " & ")` +
+		` (LINK () (FOUND "00010000000003") (TEXT "three")) (FORMAT-QUOTE () (TEXT "q")) (ENDNOTE () (TEXT "note"))))`,
+	10000000002: `(BLOCK (HEADING () 1 (TEXT "Sec")) (BLOB () "png" "iVBORw0KGgo=" (TEXT "image")) (PARA (HARD)) (VERBATIM-HTML () "<br>"))`,
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	book := epub.NewBook("A <Book>")
+	book.Identifier = "urn:test:1"
+	book.Modified = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	if err := book.FetchQuery(context.Background(), testZettel, "tags:#test"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := book.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expNames := []string{
+		"mimetype", "META-INF/container.xml", "EPUB/package.opf", "EPUB/nav.xhtml",
+		"EPUB/00010000000001.xhtml", "EPUB/00010000000002.xhtml", "EPUB/images/img1.png",
+	}
+	if len(zr.File) != len(expNames) {
+		t.Fatalf("expected %d files, but got %d", len(expNames), len(zr.File))
+	}
+	files := map[string]string{}
+	for i, f := range zr.File {
+		if f.Name != expNames[i] {
+			t.Errorf("%d: expected file %q, but got %q", i, expNames[i], f.Name)
+		}
+		rc, err2 := f.Open()
+		if err2 != nil {
+			t.Fatal(err2)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[f.Name] = string(data)
+		if strings.HasSuffix(f.Name, ".xhtml") || strings.HasSuffix(f.Name, ".opf") || strings.HasSuffix(f.Name, ".xml") {
+			if err2 = checkXML(data); err2 != nil {
+				t.Errorf("%s is not well-formed: %v\n%s", f.Name, err2, data)
+			}
+		}
+	}
+	if zr.File[0].Method != zip.Store || files["mimetype"] != "application/epub+zip" {
+		t.Errorf("invalid mimetype file: %v/%q", zr.File[0].Method, files["mimetype"])
+	}
+
+	expContains := []struct {
+		name string
+		exp  string
+	}{
+		{"EPUB/package.opf", `<dc:title>A &lt;Book&gt;</dc:title>`},
+		{"EPUB/package.opf", `<meta property="dcterms:modified">2026-10-18T12:00:00Z</meta>`},
+		{"EPUB/package.opf", `<item id="img1" href="images/img1.png" media-type="image/png"/>`},
+		{"EPUB/package.opf", `<itemref idref="z00010000000002"/>`},
+		{"EPUB/nav.xhtml", `<a href="00010000000002.xhtml">Title 00010000000002</a>`},
+		{"EPUB/00010000000001.xhtml", `href="00010000000002.xhtml#sec"`},
+		{"EPUB/00010000000001.xhtml", `<span>three</span>`},
+		{"EPUB/00010000000001.xhtml", `“q”`},
+		{"EPUB/00010000000001.xhtml", `class="zs-endnotes"`},
+		{"EPUB/00010000000002.xhtml", `<h2 id="sec">Sec</h2>`},
+		{"EPUB/00010000000002.xhtml", `src="images/img1.png"`},
+		{"EPUB/00010000000002.xhtml", `<br/>`},
+	}
+	for i, tc := range expContains {
+		if !strings.Contains(files[tc.name], tc.exp) {
+			t.Errorf("%d: %s does not contain %q:\n%s", i, tc.name, tc.exp, files[tc.name])
+		}
+	}
+	if strings.Contains(files["EPUB/00010000000002.xhtml"], "<br>") {
+		t.Error("raw HTML must not be included")
+	}
+}
+
+func checkXML(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	t.Parallel()
+	book := epub.NewBook("Error")
+	if err := book.Fetch(context.Background(), testZettel, 10000000003); err == nil {
+		t.Error("error expected")
+	}
+}
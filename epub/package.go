@@ -0,0 +1,108 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package epub
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"t73f.de/r/zsc/shtml"
+)
+
+const (
+	mimeType   = "application/epub+zip"
+	packageDir = "EPUB/"
+	navFile    = "nav.xhtml"
+
+	containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="EPUB/package.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+)
+
+// packageDocument returns the OPF package document with metadata, manifest,
+// and spine.
+func (b *Book) packageDocument(lang string, modified time.Time, images []image) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid" xml:lang="`)
+	buf.WriteString(shtml.EscapeXML(lang))
+	buf.WriteString("\">\n<metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	writeElement(&buf, `<dc:identifier id="uid">`, b.identifier(), "</dc:identifier>\n")
+	writeElement(&buf, "<dc:title>", b.Title, "</dc:title>\n")
+	writeElement(&buf, "<dc:language>", lang, "</dc:language>\n")
+	if b.Author != "" {
+		writeElement(&buf, "<dc:creator>", b.Author, "</dc:creator>\n")
+	}
+	writeElement(&buf, `<meta property="dcterms:modified">`, modified.Format(time.RFC3339), "</meta>\n")
+	buf.WriteString("</metadata>\n<manifest>\n")
+	buf.WriteString(`<item id="nav" href="` + navFile + `" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	for _, ch := range b.chapters {
+		buf.WriteString(`<item id="` + chapterID(ch) + `" href="` + chapterFile(ch.zid) + `" media-type="application/xhtml+xml"/>` + "\n")
+	}
+	for i, img := range images {
+		buf.WriteString(`<item id="img` + strconv.Itoa(i+1) + `" href="` + img.name + `" media-type="` + img.mediaType + `"/>` + "\n")
+	}
+	buf.WriteString("</manifest>\n<spine>\n")
+	for _, ch := range b.chapters {
+		buf.WriteString(`<itemref idref="` + chapterID(ch) + `"/>` + "\n")
+	}
+	buf.WriteString("</spine>\n</package>\n")
+	return buf.Bytes()
+}
+
+// navDocument returns the navigation document, i.e. the table of contents.
+func (b *Book) navDocument(lang string) []byte {
+	var buf bytes.Buffer
+	writeXHTMLStart(&buf, lang, b.Title)
+	buf.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n")
+	writeElement(&buf, "<h1>", b.Title, "</h1>\n")
+	buf.WriteString("<ol>\n")
+	for _, ch := range b.chapters {
+		writeElement(&buf, `<li><a href="`+chapterFile(ch.zid)+`">`, ch.title, "</a></li>\n")
+	}
+	buf.WriteString("</ol>\n</nav>\n")
+	writeXHTMLEnd(&buf)
+	return buf.Bytes()
+}
+
+// chapterID returns the manifest identifier of a chapter. XML identifiers
+// must not start with a digit.
+func chapterID(ch chapter) string { return "z" + ch.zid.String() }
+
+func writeElement(buf *bytes.Buffer, start, text, end string) {
+	buf.WriteString(start)
+	buf.WriteString(shtml.EscapeXML(text))
+	buf.WriteString(end)
+}
+
+func writeXHTMLStart(buf *bytes.Buffer, lang, title string) {
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="`)
+	buf.WriteString(shtml.EscapeXML(lang))
+	buf.WriteString(`" xml:lang="`)
+	buf.WriteString(shtml.EscapeXML(lang))
+	buf.WriteString("\">\n<head>\n<meta charset=\"UTF-8\"/>\n")
+	writeElement(buf, "<title>", title, "</title>\n")
+	buf.WriteString("</head>\n<body>\n")
+}
+
+func writeXHTMLEnd(buf *bytes.Buffer) {
+	buf.WriteString("</body>\n</html>\n")
+}
@@ -0,0 +1,189 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package epub
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/shtml"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsx"
+)
+
+// renderer transforms the chapters of a book into XHTML documents. It
+// collects all images that must be stored within the publication.
+type renderer struct {
+	ev       *shtml.Evaluator
+	chapters map[id.Zid]bool
+	images   []image
+}
+
+type image struct {
+	name      string
+	mediaType string
+	data      []byte
+}
+
+func newRenderer(chapters []chapter) *renderer {
+	ev := shtml.NewEvaluator(1)
+	// Raw HTML is not guaranteed to be well-formed XML.
+	ev.Rebind(zsx.SymVerbatimHTML, func(sx.Vector, *shtml.Environment) sx.Object { return sx.Nil() })
+	rd := &renderer{ev: ev, chapters: make(map[id.Zid]bool, len(chapters))}
+	for _, ch := range chapters {
+		rd.chapters[ch.zid] = true
+	}
+	return rd
+}
+
+func (rd *renderer) renderChapter(ch chapter, lang string) ([]byte, error) {
+	if ch.lang != "" {
+		lang = ch.lang
+	}
+	content, _ := sx.GetPair(rd.rewrite(ch.content))
+	env := shtml.MakeEnvironment(lang)
+	hx, err := rd.ev.Evaluate(content, &env)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeXHTMLStart(&buf, lang, ch.title)
+	writeElement(&buf, "<h1>", ch.title, "</h1>\n")
+	if err = shtml.WriteXHTML(&buf, hx); err != nil {
+		return nil, err
+	}
+	if endnotes := shtml.Endnotes(&env); endnotes != nil {
+		buf.WriteByte('\n')
+		if err = shtml.WriteXHTML(&buf, endnotes); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('\n')
+	writeXHTMLEnd(&buf)
+	return buf.Bytes(), nil
+}
+
+// rewrite returns a copy of the given sz object, where links to other zettel
+// are replaced by links to chapters and where image BLOBs are replaced by
+// references to image files.
+func (rd *renderer) rewrite(obj sx.Object) sx.Object {
+	node, isPair := sx.GetPair(obj)
+	if !isPair || node == nil {
+		return obj
+	}
+	if _, isList := sx.GetPair(node.Cdr()); !isList {
+		return obj // e.g. an attribute (key . value)
+	}
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymLink:
+		// (LINK attrs reference inline...)
+		next := node.Tail()
+		ref := rd.rewriteReference(next.Tail().Head())
+		return rd.rewriteList(next.Tail().Tail()).Cons(ref).Cons(next.Car()).Cons(zsx.SymLink)
+	case zsx.SymEmbed:
+		// (EMBED attrs reference syntax inline...)
+		// Only images stored in the publication are allowed.
+		next := node.Tail()
+		return rd.rewriteList(next.Tail().Tail().Tail()).Cons(next.Car()).Cons(zsx.SymFormatSpan)
+	case zsx.SymEmbedBLOB:
+		// (EMBED-BLOB attrs syntax data inline...)
+		if embed := rd.embedImage(node); embed != nil {
+			return embed
+		}
+	case zsx.SymBLOB:
+		// (BLOB attrs syntax data inline...)
+		if embed := rd.embedImage(node); embed != nil {
+			return sx.MakeList(zsx.SymPara, embed)
+		}
+	}
+	return rd.rewriteList(node)
+}
+
+func (rd *renderer) rewriteList(lst *sx.Pair) *sx.Pair {
+	var lb sx.ListBuilder
+	for obj := range lst.Values() {
+		lb.Add(rd.rewrite(obj))
+	}
+	return lb.List()
+}
+
+func (rd *renderer) rewriteReference(ref *sx.Pair) *sx.Pair {
+	refSym, refValue := zsx.GetReference(ref)
+	switch refSym {
+	case zsx.SymRefStateExternal, zsx.SymRefStateSelf, sz.SymRefStateBroken:
+		return ref
+	case sz.SymRefStateZettel, sz.SymRefStateFound:
+		if len(refValue) >= id.LengthZid {
+			if zid, err := id.Parse(refValue[:id.LengthZid]); err == nil && rd.chapters[zid] {
+				return zsx.MakeReference(zsx.SymRefStateHosted, chapterFile(zid)+refValue[id.LengthZid:])
+			}
+		}
+	}
+	// All other references cannot be resolved within the publication.
+	return zsx.MakeReference(zsx.SymRefStateInvalid, refValue)
+}
+
+// imageMediaTypes maps the syntax of a BLOB to the media type of an image.
+var imageMediaTypes = map[string]string{
+	meta.ValueSyntaxGif:  "image/gif",
+	meta.ValueSyntaxJPEG: "image/jpeg",
+	meta.ValueSyntaxJPG:  "image/jpeg",
+	meta.ValueSyntaxPNG:  "image/png",
+	meta.ValueSyntaxSVG:  "image/svg+xml",
+	meta.ValueSyntaxWebp: "image/webp",
+}
+
+// embedImage stores the image data of a BLOB and returns an EMBED node that
+// references it.
+func (rd *renderer) embedImage(node *sx.Pair) *sx.Pair {
+	// (EMBED-BLOB attrs syntax data inline...)
+	next := node.Tail()
+	attrs := next.Car()
+	syntaxNode := next.Tail()
+	syntax := zsx.GoValue(syntaxNode.Car())
+	mediaType, found := imageMediaTypes[syntax]
+	if !found {
+		return nil
+	}
+	dataNode := syntaxNode.Tail()
+	var data []byte
+	if syntax == meta.ValueSyntaxSVG {
+		data = []byte(zsx.GoValue(dataNode.Car()))
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(zsx.GoValue(dataNode.Car()))
+		if err != nil {
+			return nil
+		}
+		data = decoded
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	name := "images/img" + strconv.Itoa(len(rd.images)+1) + "." + strings.TrimPrefix(mediaType, "image/")
+	if syntax == meta.ValueSyntaxSVG {
+		name = strings.TrimSuffix(name, "+xml")
+	}
+	rd.images = append(rd.images, image{name: name, mediaType: mediaType, data: data})
+	return rd.rewriteList(dataNode.Tail()).
+		Cons(sx.MakeString(syntax)).
+		Cons(zsx.MakeReference(zsx.SymRefStateHosted, name)).
+		Cons(attrs).
+		Cons(zsx.SymEmbed)
+}
@@ -0,0 +1,274 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package feed generates Atom and RSS 2.0 feeds from the result of a query.
+package feed
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/shtml"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/text"
+	"t73f.de/r/zsc/webapi"
+	"t73f.de/r/zsx"
+)
+
+// Feed is a list of entries, together with some data about the feed itself.
+type Feed struct {
+	Title    string  // Title of the feed
+	Link     string  // URL of the web page that corresponds to the feed
+	Language string  // Optional language of the feed
+	Author   string  // Default author of all entries without an author
+	Entries  []Entry // Entries, most recent first
+}
+
+// Entry is a single zettel within a feed.
+type Entry struct {
+	Zid       id.Zid
+	Title     string
+	Summary   string
+	Author    string
+	Link      string
+	Published time.Time
+	Updated   time.Time
+	Content   string // Zettel content as HTML
+}
+
+// Source retrieves zettel for a feed. It is implemented by
+// [t73f.de/r/zsc/client.Client].
+type Source interface {
+	NewURLBuilder(key byte) *webapi.URLBuilder
+	QueryZettelData(ctx context.Context, query string) (string, string, []webapi.ZidMetaRights, error)
+	GetEvaluatedSz(ctx context.Context, zid id.Zid, part string) (sx.Object, error)
+}
+
+// SummaryLength is the maximum number of characters of a summary that is
+// extracted from the zettel content.
+const SummaryLength = 200
+
+// Fetch executes the query and returns a feed with all selected zettel,
+// sorted by their modification date. The author is used for all zettel
+// without an author. If it is empty, the title is used, because an Atom feed
+// needs an author for every entry.
+func Fetch(ctx context.Context, src Source, title, author, query string) (*Feed, error) {
+	_, _, metaList, err := src.QueryZettelData(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if author == "" {
+		author = title
+	}
+	feed := &Feed{
+		Title:  title,
+		Link:   src.NewURLBuilder('h').AppendQuery(query).String(),
+		Author: author,
+	}
+	ev := shtml.NewEvaluator(1)
+	for _, zmr := range metaList {
+		obj, err2 := src.GetEvaluatedSz(ctx, zmr.ID, webapi.PartContent)
+		if err2 != nil {
+			return nil, err2
+		}
+		content, isPair := sx.GetPair(obj)
+		if !isPair {
+			return nil, fmt.Errorf("content of zettel %v is not a list: %v", zmr.ID, obj)
+		}
+		entry, err2 := makeEntry(src, ev, zmr.ID, zmr.Meta, content)
+		if err2 != nil {
+			return nil, fmt.Errorf("zettel %v: %w", zmr.ID, err2)
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.SortBy(meta.KeyModified)
+	return feed, nil
+}
+
+func makeEntry(src Source, ev *shtml.Evaluator, zid id.Zid, m webapi.ZettelMeta, content *sx.Pair) (Entry, error) {
+	env := shtml.MakeEnvironment(m[meta.KeyLang])
+	rewritten, _ := sx.GetPair(rewriteLinks(src, content))
+	hx, err := ev.Evaluate(rewritten, &env)
+	if err != nil {
+		return Entry{}, err
+	}
+	var sb strings.Builder
+	if err = shtml.WriteXHTML(&sb, hx); err != nil {
+		return Entry{}, err
+	}
+	if endnotes := shtml.Endnotes(&env); endnotes != nil {
+		if err = shtml.WriteXHTML(&sb, endnotes); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	title := m[meta.KeyTitle]
+	if title == "" {
+		title = zid.String()
+	}
+	summary := m[meta.KeySummary]
+	if summary == "" {
+		summary = excerpt(text.NewLayoutEncoder(0).Encode(content), SummaryLength)
+	}
+	published := timeValue(m, meta.KeyPublished, meta.KeyCreated)
+	if published.IsZero() {
+		published, _ = meta.Value(zid.String()).AsTime()
+	}
+	updated := timeValue(m, meta.KeyModified)
+	if updated.IsZero() {
+		updated = published
+	}
+	return Entry{
+		Zid:       zid,
+		Title:     title,
+		Summary:   summary,
+		Author:    m[meta.KeyAuthor],
+		Link:      src.NewURLBuilder('h').SetZid(zid).String(),
+		Published: published,
+		Updated:   updated,
+		Content:   sb.String(),
+	}, nil
+}
+
+func timeValue(m webapi.ZettelMeta, keys ...string) time.Time {
+	for _, key := range keys {
+		if val, found := m[key]; found {
+			if t, ok := meta.Value(val).AsTime(); ok {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// excerpt returns the first words of the given text, not longer than the
+// given number of characters.
+func excerpt(s string, maxLen int) string {
+	var sb strings.Builder
+	length := 0
+	for word := range strings.FieldsSeq(s) {
+		wordLen := utf8.RuneCountInString(word)
+		if length > 0 {
+			if length+1+wordLen > maxLen {
+				sb.WriteString(" …")
+				break
+			}
+			sb.WriteByte(' ')
+			length++
+		}
+		sb.WriteString(word)
+		length += wordLen
+	}
+	return sb.String()
+}
+
+// SortBy sorts the entries of the feed in descending order, either by their
+// modification date (key "modified") or by their publishing date (key
+// "published").
+func (f *Feed) SortBy(key string) {
+	slices.SortStableFunc(f.Entries, func(a, b Entry) int {
+		ta, tb := a.Updated, b.Updated
+		if key == meta.KeyPublished {
+			ta, tb = a.Published, b.Published
+		}
+		if c := tb.Compare(ta); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Zid, a.Zid)
+	})
+}
+
+// Updated returns the most recent modification date of all entries.
+func (f *Feed) Updated() time.Time {
+	var result time.Time
+	for _, e := range f.Entries {
+		if e.Updated.After(result) {
+			result = e.Updated
+		}
+	}
+	return result
+}
+
+// rewriteLinks returns a copy of the sz object, where all references to
+// zettel are replaced by absolute URLs. Links refer to the HTML page of a
+// zettel, embedded and transcluded zettel to its content, e.g. an image.
+func rewriteLinks(src Source, obj sx.Object) sx.Object {
+	node, isPair := sx.GetPair(obj)
+	if !isPair || node == nil {
+		return obj
+	}
+	if _, isList := sx.GetPair(node.Cdr()); !isList {
+		return obj // e.g. an attribute (key . value)
+	}
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymLink:
+		// (LINK attrs reference inline...)
+		return rewriteReference(src, node, 'h')
+	case zsx.SymEmbed, zsx.SymTransclude:
+		// (EMBED attrs reference syntax inline...), (TRANSCLUDE attrs reference inline...)
+		return rewriteReference(src, node, 'z')
+	}
+	return rewriteList(src, node)
+}
+
+// rewriteReference rewrites the reference of a node, which follows the
+// attributes, into an absolute URL with the given key.
+func rewriteReference(src Source, node *sx.Pair, key byte) *sx.Pair {
+	next := node.Tail()
+	ref := next.Tail().Head()
+	if u := absoluteURL(src, ref, key); u != "" {
+		ref = zsx.MakeReference(zsx.SymRefStateExternal, u)
+	}
+	return rewriteList(src, next.Tail().Tail()).Cons(ref).Cons(next.Car()).Cons(node.Car())
+}
+
+func rewriteList(src Source, lst *sx.Pair) *sx.Pair {
+	var lb sx.ListBuilder
+	for obj := range lst.Values() {
+		lb.Add(rewriteLinks(src, obj))
+	}
+	return lb.List()
+}
+
+func absoluteURL(src Source, ref *sx.Pair, key byte) string {
+	refSym, refValue := zsx.GetReference(ref)
+	switch refSym {
+	case sz.SymRefStateZettel, sz.SymRefStateFound:
+		if len(refValue) < id.LengthZid {
+			return ""
+		}
+		zid, err := id.Parse(refValue[:id.LengthZid])
+		if err != nil {
+			return ""
+		}
+		ub := src.NewURLBuilder(key).SetZid(zid)
+		if key == 'h' && len(refValue) > id.LengthZid+1 {
+			ub.SetFragment(refValue[id.LengthZid+1:])
+		}
+		return ub.String()
+	case sz.SymRefStateQuery:
+		if key != 'h' {
+			return ""
+		}
+		return src.NewURLBuilder('h').AppendQuery(refValue).String()
+	}
+	return ""
+}
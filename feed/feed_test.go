@@ -0,0 +1,154 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package feed_test
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/feed"
+	"t73f.de/r/zsc/webapi"
+)
+
+type testSource struct{}
+
+func (testSource) NewURLBuilder(key byte) *webapi.URLBuilder {
+	return webapi.NewURLBuilder("https://zs.example/", key)
+}
+
+func (testSource) QueryZettelData(context.Context, string) (string, string, []webapi.ZidMetaRights, error) {
+	return "", "", []webapi.ZidMetaRights{
+		{ID: 20260101120000, Meta: webapi.ZettelMeta{
+			meta.KeyTitle: "Old", meta.KeyModified: "20260301120000", meta.KeyPublished: "20260101120000",
+		}},
+		{ID: 20260201120000, Meta: webapi.ZettelMeta{
+			meta.KeyTitle: "New", meta.KeySummary: "A <summary>", meta.KeyAuthor: "Me",
+		}},
+	}, nil
+}
+
+var testContent = map[id.Zid]string{
+	20260101120000: `(BLOCK (PARA (TEXT "Some long text") (SOFT) (LINK () (FOUND "20260201120000#x") (TEXT "link"))) (PARA (TEXT "more") (EMBED () (FOUND "20260101120001") "png")) (TRANSCLUDE () (ZETTEL "20260101120002")))`,
+	20260201120000: `(BLOCK (PARA (FORMAT-EMPH () (TEXT "new"))))`,
+}
+
+func (testSource) GetEvaluatedSz(_ context.Context, zid id.Zid, _ string) (sx.Object, error) {
+	return sxreader.MakeReader(strings.NewReader(testContent[zid])).Read()
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+	f, err := feed.Fetch(context.Background(), testSource{}, "Journal", "", "tags:#journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Author != "Journal" {
+		t.Errorf("title expected as default author, but got %q", f.Author)
+	}
+	if exp := "https://zs.example/h?q=tags%3A%23journal"; f.Link != exp {
+		t.Errorf("expected link %q, but got %q", exp, f.Link)
+	}
+	if len(f.Entries) != 2 {
+		t.Fatalf("expected 2 entries, but got %d", len(f.Entries))
+	}
+	old := f.Entries[0]
+	if old.Title != "Old" {
+		t.Errorf("entry modified last must be first, but got %q", old.Title)
+	}
+	if exp := "Some long text link more"; old.Summary != exp {
+		t.Errorf("expected summary %q, but got %q", exp, old.Summary)
+	}
+	if exp := `href="https://zs.example/h/20260201120000#x"`; !strings.Contains(old.Content, exp) {
+		t.Errorf("content %q does not contain %q", old.Content, exp)
+	}
+	for _, exp := range []string{`src="https://zs.example/z/20260101120001"`, `src="https://zs.example/z/20260101120002"`} {
+		if !strings.Contains(old.Content, exp) {
+			t.Errorf("content %q does not contain %q", old.Content, exp)
+		}
+	}
+	if exp := "https://zs.example/h/20260101120000"; old.Link != exp {
+		t.Errorf("expected entry link %q, but got %q", exp, old.Link)
+	}
+
+	f.SortBy(meta.KeyPublished)
+	if got := f.Entries[0].Title; got != "New" {
+		t.Errorf("entry published last must be first, but got %q", got)
+	}
+	if got := f.Entries[0].Content; got != "<p><em>new</em></p>" {
+		t.Errorf("unexpected content %q", got)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	t.Parallel()
+	f, err := feed.Fetch(context.Background(), testSource{}, "Journal", "", "tags:#journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err = f.WriteAtom(&sb); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	for i, exp := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<updated>2026-03-01T12:00:00Z</updated>`,
+		`<id>https://zs.example/h/20260201120000</id>`,
+		`<summary>A &lt;summary&gt;</summary>`,
+		`<content type="html">&lt;p&gt;&lt;em&gt;new&lt;/em&gt;&lt;/p&gt;</content>`,
+		`<name>Me</name>`,
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("%d: %q not found in\n%s", i, exp, got)
+		}
+	}
+	// The feed and the entry without an author use the default author.
+	if n := strings.Count(got, "<name>Journal</name>"); n != 2 {
+		t.Errorf("default author expected 2 times, but got %d times in\n%s", n, got)
+	}
+	if err = xml.Unmarshal([]byte(got), new(any)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	t.Parallel()
+	f, err := feed.Fetch(context.Background(), testSource{}, "Journal", "Editor", "tags:#journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err = f.WriteRSS(&sb); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	for i, exp := range []string{
+		`<rss version="2.0"`,
+		`<lastBuildDate>Sun, 01 Mar 2026 12:00:00 +0000</lastBuildDate>`,
+		`<guid isPermaLink="true">https://zs.example/h/20260201120000</guid>`,
+		`<pubDate>Thu, 01 Jan 2026 12:00:00 +0000</pubDate>`,
+		`<dc:creator>Me</dc:creator>`,
+		`<dc:creator>Editor</dc:creator>`,
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("%d: %q not found in\n%s", i, exp, got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr,omitempty"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Link      atomLink    `xml:"link"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   *atomText   `xml:"content,omitempty"`
+}
+
+// WriteAtom writes the feed as an Atom document.
+func (f *Feed) WriteAtom(w io.Writer) error {
+	af := atomFeed{
+		Lang:    f.Language,
+		ID:      f.Link,
+		Title:   f.Title,
+		Updated: atomTime(f.Updated()),
+		Link:    atomLink{Href: f.Link},
+		Author:  makeAtomAuthor(f.Author),
+	}
+	for _, e := range f.Entries {
+		ae := atomEntry{
+			ID:      e.Link,
+			Title:   e.Title,
+			Updated: atomTime(e.Updated),
+			Author:  makeAtomAuthor(e.Author),
+			Link:    atomLink{Href: e.Link},
+		}
+		if ae.Author == nil {
+			ae.Author = makeAtomAuthor(f.Author)
+		}
+		if !e.Published.IsZero() {
+			ae.Published = atomTime(e.Published)
+		}
+		if e.Summary != "" {
+			ae.Summary = &atomText{Text: e.Summary}
+		}
+		if e.Content != "" {
+			ae.Content = &atomText{Type: "html", Text: e.Content}
+		}
+		af.Entries = append(af.Entries, ae)
+	}
+	return writeXML(w, af)
+}
+
+func makeAtomAuthor(name string) *atomAuthor {
+	if name == "" {
+		return nil
+	}
+	return &atomAuthor{Name: name}
+}
+
+func atomTime(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	NSDC    string     `xml:"xmlns:dc,attr"`
+	NSCont  string     `xml:"xmlns:content,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	Content     string  `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes the feed as a RSS 2.0 document.
+func (f *Feed) WriteRSS(w io.Writer) error {
+	ch := rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Title,
+		Language:    f.Language,
+	}
+	if updated := f.Updated(); !updated.IsZero() {
+		ch.LastBuildDate = rssTime(updated)
+	}
+	for _, e := range f.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: e.Link},
+			Creator:     e.Author,
+			Description: e.Summary,
+			Content:     e.Content,
+		}
+		if item.Creator == "" {
+			item.Creator = f.Author
+		}
+		if !e.Published.IsZero() {
+			item.PubDate = rssTime(e.Published)
+		}
+		ch.Items = append(ch.Items, item)
+	}
+	return writeXML(w, rssDocument{
+		Version: "2.0",
+		NSDC:    "http://purl.org/dc/elements/1.1/",
+		NSCont:  "http://purl.org/rss/1.0/modules/content/",
+		Channel: ch,
+	})
+}
+
+func rssTime(t time.Time) string { return t.UTC().Format(time.RFC1123Z) }
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", " ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -3,6 +3,7 @@ module t73f.de/r/zsc
 go 1.26
 
 require (
+	golang.org/x/crypto v0.54.0
 	t73f.de/r/sx v0.0.0-20260707123451-9afa5b03bb8a
 	t73f.de/r/sxwebs v0.0.0-20260707123716-eed127fbf809
 	t73f.de/r/webs v0.0.0-20260707123138-a0fd2693c130
@@ -10,4 +11,4 @@ require (
 	t73f.de/r/zsx v0.0.0-20260723111354-fc39b16648ed
 )
 
-require golang.org/x/text v0.39.0 // indirect
+require golang.org/x/text v0.40.0 // indirect
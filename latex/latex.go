@@ -0,0 +1,601 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package latex encodes a s-expr encoded zettel AST as LaTeX.
+//
+// The produced LaTeX code is a document body. It relies on the packages
+// listed in [Preamble].
+package latex
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/shtml"
+	"t73f.de/r/zsx"
+)
+
+// Preamble contains the LaTeX packages that are needed by the encoded body.
+const Preamble = `\usepackage[T1]{fontenc}
+\usepackage[normalem]{ulem}
+\usepackage{xcolor}
+\usepackage{soul}
+\usepackage{listings}
+\lstnewenvironment{zsclisting}{}{}
+\usepackage{hyperref}
+`
+
+// Encoder encodes a sz tree as LaTeX.
+type Encoder struct {
+	headingOffset int
+	buf           bytes.Buffer
+	langStack     shtml.LangStack
+	quoteNesting  uint
+	inHeading     bool // A heading is a moving argument, e.g. for the table of contents
+	bracketPos    int  // Output position where a "[" must be protected, or -1
+	err           error
+}
+
+// NewEncoder creates a new LaTeX encoder. Like [shtml.NewEvaluator], the
+// heading offset is added to the level of each heading.
+func NewEncoder(headingOffset int) *Encoder {
+	return &Encoder{headingOffset: headingOffset}
+}
+
+// Encode the given sz node, typically a BLOCK or INLINE node, as LaTeX and
+// write it to the writer. The language is used to select quote marks.
+func (enc *Encoder) Encode(w io.Writer, node *sx.Pair, lang string) error {
+	enc.buf.Reset()
+	enc.langStack = shtml.NewLangStack(lang)
+	enc.quoteNesting = 0
+	enc.inHeading = false
+	enc.bracketPos = -1
+	enc.err = nil
+
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymBlock:
+		enc.writeBlocks(node.Tail())
+	case zsx.SymInline:
+		enc.writeInlines(node.Tail())
+		enc.buf.WriteByte('\n')
+	default:
+		enc.writeBlock(node)
+	}
+	if enc.err != nil {
+		return enc.err
+	}
+	_, err := enc.buf.WriteTo(w)
+	return err
+}
+
+func (enc *Encoder) pushAttributes(attrs *sx.Pair) {
+	if lang, found := zsx.GetAttributes(attrs).Get("lang"); found {
+		enc.langStack.Push(lang)
+	} else {
+		enc.langStack.Dup()
+	}
+}
+
+func (enc *Encoder) popAttributes() { enc.langStack.Pop() }
+
+// writeBlocks writes the given blocks, separated by an empty line.
+func (enc *Encoder) writeBlocks(lst *sx.Pair) {
+	first := true
+	for obj := range lst.Values() {
+		node, isPair := sx.GetPair(obj)
+		if !isPair || node == nil {
+			continue
+		}
+		pos := enc.buf.Len()
+		if !first {
+			enc.buf.WriteByte('\n')
+		}
+		start := enc.buf.Len()
+		enc.writeBlock(node)
+		if enc.buf.Len() == start {
+			enc.buf.Truncate(pos)
+			continue
+		}
+		first = false
+	}
+}
+
+func (enc *Encoder) writeBlock(node *sx.Pair) {
+	switch sym := zsx.NodeSymbol(node); sym {
+	case zsx.SymBlock:
+		enc.writeBlocks(node.Tail())
+	case zsx.SymPara:
+		enc.writeInlines(node.Tail())
+		enc.buf.WriteByte('\n')
+	case zsx.SymHeading:
+		enc.writeHeading(node)
+	case zsx.SymThematic:
+		enc.buf.WriteString("\\begin{center}\\rule{0.5\\linewidth}{0.4pt}\\end{center}\n")
+	case zsx.SymListOrdered:
+		enc.writeList(node, "enumerate")
+	case zsx.SymListUnordered:
+		enc.writeList(node, "itemize")
+	case zsx.SymListQuote:
+		enc.writeQuoteList(node)
+	case zsx.SymDescription:
+		enc.writeDescription(node)
+	case zsx.SymTable:
+		enc.writeTable(node)
+	case zsx.SymRegionBlock:
+		enc.writeRegion(node, "")
+	case zsx.SymRegionQuote:
+		enc.writeRegion(node, "quotation")
+	case zsx.SymRegionVerse:
+		enc.writeRegion(node, "verse")
+	case zsx.SymVerbatimCode, zsx.SymVerbatimEval, zsx.SymVerbatimZettel:
+		enc.writeListing(node)
+	case zsx.SymVerbatimMath:
+		if _, _, content := zsx.GetLiteral(node); content != "" {
+			enc.buf.WriteString("\\[\n")
+			enc.buf.WriteString(strings.TrimRight(content, "\n"))
+			enc.buf.WriteString("\n\\]\n")
+		}
+	case zsx.SymBLOB:
+		// (BLOB attrs syntax data inline...)
+		if text := node.Tail().Tail().Tail().Tail(); text != nil {
+			enc.writeInlines(text)
+			enc.buf.WriteByte('\n')
+		}
+	}
+}
+
+var sectionCommands = []string{"section", "subsection", "subsubsection", "paragraph", "subparagraph"}
+
+func (enc *Encoder) writeHeading(node *sx.Pair) {
+	// (HEADING attrs level inline...)
+	attrsNode := node.Tail()
+	levelNode := attrsNode.Tail()
+	num, isInt := levelNode.Car().(sx.Int64)
+	if !isInt || num <= 0 {
+		enc.err = fmt.Errorf("%v is not a valid heading level", levelNode.Car())
+		return
+	}
+	level := int(num) + enc.headingOffset
+	if level <= 0 {
+		enc.err = fmt.Errorf("%v is a negative heading level", level)
+		return
+	}
+	enc.buf.WriteByte('\\')
+	enc.buf.WriteString(sectionCommands[min(level, len(sectionCommands))-1])
+	enc.buf.WriteByte('{')
+	enc.inHeading = true
+	enc.writeInlines(levelNode.Tail())
+	enc.inHeading = false
+	enc.buf.WriteByte('}')
+	if label, found := zsx.GetAttributes(attrsNode.Head()).Get(zsx.SymSpecialID.GetValue()); found && label != "" {
+		enc.writeLabel(label)
+	}
+	enc.buf.WriteByte('\n')
+}
+
+func (enc *Encoder) writeLabel(label string) {
+	enc.buf.WriteString("\\label{")
+	enc.buf.WriteString(escapeURL(label))
+	enc.buf.WriteByte('}')
+}
+
+func (enc *Encoder) writeList(node *sx.Pair, env string) {
+	// (ORDERED attrs (ITEM attrs block...)...)
+	items := node.Tail().Tail()
+	if items == nil {
+		return
+	}
+	enc.buf.WriteString("\\begin{" + env + "}\n")
+	for obj := range items.Values() {
+		item, isPair := sx.GetPair(obj)
+		if !isPair || item == nil {
+			continue
+		}
+		_, elems := zsx.GetListItem(item)
+		enc.buf.WriteString("\\item ")
+		enc.bracketPos = enc.buf.Len()
+		if elems == nil {
+			enc.buf.WriteByte('\n')
+		}
+		enc.writeBlocks(elems)
+	}
+	enc.buf.WriteString("\\end{" + env + "}\n")
+}
+
+func (enc *Encoder) writeQuoteList(node *sx.Pair) {
+	// (QUOTATION attrs (ITEM attrs block...)...)
+	items := node.Tail().Tail()
+	if items == nil {
+		return
+	}
+	enc.buf.WriteString("\\begin{quote}\n")
+	first := true
+	for obj := range items.Values() {
+		item, isPair := sx.GetPair(obj)
+		if !isPair || item == nil {
+			continue
+		}
+		if !first {
+			enc.buf.WriteByte('\n')
+		}
+		first = false
+		_, elems := zsx.GetListItem(item)
+		enc.writeBlocks(elems)
+	}
+	enc.buf.WriteString("\\end{quote}\n")
+}
+
+func (enc *Encoder) writeDescription(node *sx.Pair) {
+	// (DESCRIPTION attrs (TERM attrs inline...) (DETAIL (ENTRY attrs block...)...)...)
+	_, rest := zsx.GetDescription(node)
+	if rest == nil {
+		return
+	}
+	enc.buf.WriteString("\\begin{description}\n")
+	for obj := range rest.Values() {
+		elem, isPair := sx.GetPair(obj)
+		if !isPair || elem == nil {
+			continue
+		}
+		switch zsx.NodeSymbol(elem) {
+		case zsx.SymTerm:
+			enc.buf.WriteString("\\item[{")
+			enc.writeInlines(elem.Tail().Tail())
+			enc.buf.WriteString("}]\n")
+		case zsx.SymDetail:
+			first := true
+			for entryObj := range elem.Tail().Values() {
+				if entry, isEntry := sx.GetPair(entryObj); isEntry && entry != nil {
+					if !first {
+						enc.buf.WriteByte('\n')
+					}
+					first = false
+					enc.writeBlocks(entry.Tail().Tail())
+				}
+			}
+		}
+	}
+	enc.buf.WriteString("\\end{description}\n")
+}
+
+func (enc *Encoder) writeRegion(node *sx.Pair, env string) {
+	// (REGION-BLOCK attrs (block...) inline...)
+	attrsNode := node.Tail()
+	enc.pushAttributes(attrsNode.Head())
+	defer enc.popAttributes()
+
+	next := attrsNode.Tail()
+	if env != "" {
+		enc.buf.WriteString("\\begin{" + env + "}\n")
+	}
+	enc.writeBlocks(next.Head())
+	if cite := next.Tail(); cite != nil {
+		enc.buf.WriteString("\n\\hfill---~")
+		enc.writeInlines(cite)
+		enc.buf.WriteByte('\n')
+	}
+	if env != "" {
+		enc.buf.WriteString("\\end{" + env + "}\n")
+	}
+}
+
+// listingEnvs are the environments to typeset a listing. A listing ends at the
+// first line that ends its environment, which cannot be escaped. Therefore, the
+// first environment that does not occur in the content is used.
+var listingEnvs = []string{"lstlisting", "zsclisting"}
+
+func (enc *Encoder) writeListing(node *sx.Pair) {
+	_, _, content := zsx.GetLiteral(node)
+	if content == "" {
+		return
+	}
+	content = strings.TrimRight(content, "\n")
+	env := ""
+	for _, candidate := range listingEnvs {
+		if !strings.Contains(content, "\\end{"+candidate+"}") {
+			env = candidate
+			break
+		}
+	}
+	if env == "" {
+		// All environments occur in the content. The content must be changed
+		// to end the listing correctly.
+		env = listingEnvs[0]
+		content = strings.ReplaceAll(content, "\\end{"+env+"}", "\\end {"+env+"}")
+	}
+	enc.buf.WriteString("\\begin{" + env + "}\n")
+	enc.buf.WriteString(content)
+	enc.buf.WriteString("\n\\end{" + env + "}\n")
+}
+
+func (enc *Encoder) writeTable(node *sx.Pair) {
+	// (TABLE attrs header-row row...)
+	next := node.Tail().Tail()
+	header := next.Head()
+	var rows []*sx.Pair
+	if header != nil {
+		rows = append(rows, header)
+	}
+	for obj := range next.Tail().Values() {
+		if row, isPair := sx.GetPair(obj); isPair && row != nil {
+			rows = append(rows, row)
+		}
+	}
+
+	var aligns []byte
+	for _, row := range rows {
+		_, cells := zsx.GetRow(row)
+		i := 0
+		for obj := range cells.Values() {
+			if cell, isPair := sx.GetPair(obj); isPair && cell != nil {
+				if i >= len(aligns) {
+					aligns = append(aligns, cellAlignment(cell))
+				}
+				i++
+			}
+		}
+	}
+	if len(aligns) == 0 {
+		return
+	}
+
+	enc.buf.WriteString("\\begin{tabular}{|")
+	for _, align := range aligns {
+		enc.buf.WriteByte(align)
+		enc.buf.WriteByte('|')
+	}
+	enc.buf.WriteString("}\n\\hline\n")
+	for i, row := range rows {
+		enc.writeTableRow(row, len(aligns))
+		if i == 0 && header != nil {
+			enc.buf.WriteString("\\hline\n")
+		}
+	}
+	enc.buf.WriteString("\\hline\n\\end{tabular}\n")
+}
+
+func (enc *Encoder) writeTableRow(row *sx.Pair, numCols int) {
+	_, cells := zsx.GetRow(row)
+	col := 0
+	for obj := range cells.Values() {
+		cell, isPair := sx.GetPair(obj)
+		if !isPair || cell == nil {
+			continue
+		}
+		if col > 0 {
+			enc.buf.WriteString(" & ")
+		}
+		// (CELL attrs inline...)
+		enc.writeInlines(cell.Tail().Tail())
+		col++
+	}
+	for ; col < numCols; col++ {
+		if col > 0 {
+			enc.buf.WriteString(" & ")
+		}
+	}
+	enc.buf.WriteString(" \\\\\n")
+	enc.bracketPos = enc.buf.Len()
+}
+
+func cellAlignment(cell *sx.Pair) byte {
+	if alignPair := cell.Tail().Head().Assoc(zsx.SymAttrAlign); alignPair != nil {
+		if align, isString := sx.GetString(alignPair.Cdr()); isString {
+			switch align.GetValue() {
+			case zsx.AttrAlignCenter.GetValue():
+				return 'c'
+			case zsx.AttrAlignRight.GetValue():
+				return 'r'
+			}
+		}
+	}
+	return 'l'
+}
+
+func (enc *Encoder) writeInlines(lst *sx.Pair) {
+	for obj := range lst.Values() {
+		if node, isPair := sx.GetPair(obj); isPair && node != nil {
+			enc.writeInline(node)
+		}
+	}
+}
+
+func (enc *Encoder) writeInline(node *sx.Pair) {
+	switch sym := zsx.NodeSymbol(node); sym {
+	case zsx.SymText:
+		if s, isString := sx.GetString(node.Tail().Car()); isString {
+			text := s.GetValue()
+			if enc.buf.Len() == enc.bracketPos && strings.HasPrefix(text, "[") {
+				// Otherwise, it would start an optional argument of \item or \\.
+				enc.buf.WriteString("{}")
+			}
+			enc.buf.WriteString(Escape(text))
+		}
+	case zsx.SymSoft:
+		enc.buf.WriteByte('\n')
+	case zsx.SymHard:
+		enc.buf.WriteString("\\\\\n")
+		enc.bracketPos = enc.buf.Len()
+	case zsx.SymLink:
+		enc.writeLink(node)
+	case zsx.SymEmbed:
+		// (EMBED attrs reference syntax inline...)
+		enc.writeInlines(node.Tail().Tail().Tail().Tail())
+	case zsx.SymEmbedBLOB:
+		// (EMBED-BLOB attrs syntax data inline...)
+		enc.writeInlines(node.Tail().Tail().Tail().Tail())
+	case zsx.SymCite:
+		// (CITE attrs key inline...)
+		next := node.Tail().Tail()
+		enc.buf.WriteString("\\cite")
+		if text := next.Tail(); text != nil {
+			enc.buf.WriteString("[{")
+			enc.writeInlines(text)
+			enc.buf.WriteString("}]")
+		}
+		enc.buf.WriteByte('{')
+		if key, isString := sx.GetString(next.Car()); isString {
+			enc.buf.WriteString(key.GetValue())
+		}
+		enc.buf.WriteByte('}')
+	case zsx.SymMark:
+		// (MARK attrs mark inline...)
+		attrsNode := node.Tail()
+		if label, found := zsx.GetAttributes(attrsNode.Head()).Get(zsx.SymSpecialID.GetValue()); found && label != "" {
+			enc.writeLabel(label)
+		}
+		enc.writeInlines(attrsNode.Tail().Tail())
+	case zsx.SymEndnote:
+		if enc.inHeading {
+			enc.writeFormat(node, "\\protect\\footnote{", "}")
+		} else {
+			enc.writeFormat(node, "\\footnote{", "}")
+		}
+	case zsx.SymFormatQuote:
+		enc.writeQuote(node)
+	case zsx.SymFormatDelete:
+		enc.writeFormat(node, "\\sout{", "}")
+	case zsx.SymFormatEmph:
+		enc.writeFormat(node, "\\emph{", "}")
+	case zsx.SymFormatInsert:
+		enc.writeFormat(node, "\\uline{", "}")
+	case zsx.SymFormatMark:
+		enc.writeFormat(node, "\\hl{", "}")
+	case zsx.SymFormatSpan:
+		enc.writeFormat(node, "", "")
+	case zsx.SymFormatStrong:
+		enc.writeFormat(node, "\\textbf{", "}")
+	case zsx.SymFormatSub:
+		enc.writeFormat(node, "\\textsubscript{", "}")
+	case zsx.SymFormatSuper:
+		enc.writeFormat(node, "\\textsuperscript{", "}")
+	case zsx.SymLiteralCode, zsx.SymLiteralInput, zsx.SymLiteralOutput:
+		_, _, s := zsx.GetLiteral(node)
+		enc.buf.WriteString("\\texttt{")
+		enc.buf.WriteString(Escape(s))
+		enc.buf.WriteByte('}')
+	case zsx.SymLiteralMath:
+		if _, _, s := zsx.GetLiteral(node); s != "" {
+			enc.buf.WriteString("\\(")
+			enc.buf.WriteString(s)
+			enc.buf.WriteString("\\)")
+		}
+	case zsx.SymInline:
+		enc.writeInlines(node.Tail())
+	}
+}
+
+func (enc *Encoder) writeLink(node *sx.Pair) {
+	// (LINK attrs reference inline...)
+	ref := node.Tail().Tail()
+	refSym, refValue := zsx.GetReference(ref.Head())
+	text := ref.Tail()
+	if refSym != zsx.SymRefStateExternal {
+		if text != nil {
+			enc.writeInlines(text)
+		} else {
+			enc.buf.WriteString(Escape(refValue))
+		}
+		return
+	}
+	if text == nil {
+		enc.buf.WriteString("\\url{")
+		enc.buf.WriteString(escapeURL(refValue))
+		enc.buf.WriteByte('}')
+		return
+	}
+	enc.buf.WriteString("\\href{")
+	enc.buf.WriteString(escapeURL(refValue))
+	enc.buf.WriteString("}{")
+	enc.writeInlines(text)
+	enc.buf.WriteByte('}')
+}
+
+func (enc *Encoder) writeFormat(node *sx.Pair, prefix, suffix string) {
+	// (FORMAT-xxx attrs inline...)
+	attrsNode := node.Tail()
+	enc.pushAttributes(attrsNode.Head())
+	defer enc.popAttributes()
+	enc.buf.WriteString(prefix)
+	enc.writeInlines(attrsNode.Tail())
+	enc.buf.WriteString(suffix)
+}
+
+func (enc *Encoder) writeQuote(node *sx.Pair) {
+	// (FORMAT-QUOTE attrs inline...)
+	attrsNode := node.Tail()
+	enc.pushAttributes(attrsNode.Head())
+	defer enc.popAttributes()
+
+	qi := shtml.GetQuoteInfo(enc.langStack.Top())
+	leftQ, rightQ := qi.GetQuotes(enc.quoteNesting)
+	enc.buf.WriteString(quoteEntities[leftQ])
+	if qi.GetNBSp() {
+		enc.buf.WriteByte('~')
+	}
+	enc.quoteNesting++
+	enc.writeInlines(attrsNode.Tail())
+	enc.quoteNesting--
+	if qi.GetNBSp() {
+		enc.buf.WriteByte('~')
+	}
+	enc.buf.WriteString(quoteEntities[rightQ])
+}
+
+// quoteEntities maps the HTML entities of [shtml.QuoteInfo] to LaTeX.
+var quoteEntities = map[string]string{
+	"&quot;":   "\\textquotedbl{}",
+	"&ldquo;":  "\\textquotedblleft{}",
+	"&rdquo;":  "\\textquotedblright{}",
+	"&lsquo;":  "\\textquoteleft{}",
+	"&rsquo;":  "\\textquoteright{}",
+	"&bdquo;":  "\\quotedblbase{}",
+	"&sbquo;":  "\\quotesinglbase{}",
+	"&laquo;":  "\\guillemotleft{}",
+	"&raquo;":  "\\guillemotright{}",
+	"&lsaquo;": "\\guilsinglleft{}",
+	"&rsaquo;": "\\guilsinglright{}",
+}
+
+var textEscaper = strings.NewReplacer(
+	"\\", "\\textbackslash{}",
+	"{", "\\{",
+	"}", "\\}",
+	"$", "\\$",
+	"&", "\\&",
+	"#", "\\#",
+	"%", "\\%",
+	"_", "\\_",
+	"^", "\\textasciicircum{}",
+	"~", "\\textasciitilde{}",
+	"<", "\\textless{}",
+	">", "\\textgreater{}",
+	"|", "\\textbar{}",
+	"\u00a0", "~",
+)
+
+// Escape the given string, so that LaTeX will typeset it as is.
+func Escape(s string) string { return textEscaper.Replace(s) }
+
+var urlEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"{", "\\{",
+	"}", "\\}",
+	"#", "\\#",
+	"%", "\\%",
+)
+
+func escapeURL(s string) string { return urlEscaper.Replace(s) }
@@ -0,0 +1,94 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package latex_test
+
+import (
+	"strings"
+	"testing"
+
+	"t73f.de/r/zsc/latex"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/sz/zmk"
+	"t73f.de/r/zsx/input"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src  string
+		lang string
+		exp  string
+	}{
+		{"", "", ""},
+		{"abc def", "", "abc def\n"},
+		{"a\nb\n\nc", "", "a\nb\n\nc\n"},
+		{"$1 & 2% #3_4^5~{6}\\", "", "\\$1 \\& 2\\% \\#3\\_4\\textasciicircum{}5\\textasciitilde{}\\{6\\}\\textbackslash{}\n"},
+		{"=== Head", "", "\\section{Head}\\label{head}\n"},
+		{"==== Sub", "", "\\subsection{Sub}\\label{sub}\n"},
+		{"* a\n* b\n** c", "", "\\begin{itemize}\n\\item a\n\\item b\n\n\\begin{itemize}\n\\item c\n\\end{itemize}\n\\end{itemize}\n"},
+		{"# a\n# b", "", "\\begin{enumerate}\n\\item a\n\\item b\n\\end{enumerate}\n"},
+		{"> a", "", "\\begin{quote}\na\n\\end{quote}\n"},
+		{"; t\n: d", "", "\\begin{description}\n\\item[{t}]\nd\n\\end{description}\n"},
+		{"|=a|=b>\n|c|d", "", "\\begin{tabular}{|l|r|}\n\\hline\na & b \\\\\n\\hline\nc & d \\\\\n\\hline\n\\end{tabular}\n"},
+		{"|a|b\n|[x]|d", "", "\\begin{tabular}{|l|l|}\n\\hline\na & b \\\\\n{}[x] & d \\\\\n\\hline\n\\end{tabular}\n"},
+		{"<<<\nq\n<<< c", "", "\\begin{quotation}\nq\n\n\\hfill---~c\n\\end{quotation}\n"},
+		{"```\nx := {1}\n```", "", "\\begin{lstlisting}\nx := {1}\n\\end{lstlisting}\n"},
+		{"```\n\\end{lstlisting}\n```", "", "\\begin{zsclisting}\n\\end{lstlisting}\n\\end{zsclisting}\n"},
+		{"```\n\\end{lstlisting}\\end{zsclisting}\n```", "", "\\begin{lstlisting}\n\\end {lstlisting}\\end{zsclisting}\n\\end{lstlisting}\n"},
+		{"$$$\n\\sum\n$$$", "", "\\[\n\\sum\n\\]\n"},
+		{"a[^n] b", "", "a\\footnote{n} b\n"},
+		{"=== A[^n]", "", "\\section{A\\protect\\footnote{n}}\\label{an}\n"},
+		{"* [x] done\n* [[l|https://t73f.de]] [y]", "", "\\begin{itemize}\n\\item {}[x] done\n\\item \\href{https://t73f.de}{l} [y]\n\\end{itemize}\n"},
+		{"a%%\n[b]", "", "a\\\\\n{}[b]\n"},
+		{"a [b]", "", "a [b]\n"},
+		{"$$x_1$$", "", "\\(x_1\\)\n"},
+		{"``a_b``", "", "\\texttt{a\\_b}\n"},
+		{"__e__ **s**", "", "\\emph{e} \\textbf{s}\n"},
+		{"[[t|https://t73f.de/#x]]", "", "\\href{https://t73f.de/\\#x}{t}\n"},
+		{"\"\"a\"\"", "", "\\textquotedbl{}a\\textquotedbl{}\n"},
+		{"\"\"a\"\"", "en", "\\textquotedblleft{}a\\textquotedblright{}\n"},
+		{"\"\"a\"\"", "de", "\\quotedblbase{}a\\textquotedblleft{}\n"},
+		{"\"\"a\"\"{lang=fr}", "en", "\\guillemotleft{}~a~\\guillemotright{}\n"},
+	}
+	var parser zmk.Parser
+	for i, tc := range testcases {
+		parser.Initialize(input.NewInput([]byte(tc.src)))
+		ast := parser.Parse()
+		sz.AssignIdentifier(ast)
+		var sb strings.Builder
+		if err := latex.NewEncoder(0).Encode(&sb, ast, tc.lang); err != nil {
+			t.Errorf("%d: %q: error: %v", i, tc.src, err)
+			continue
+		}
+		if got := sb.String(); got != tc.exp {
+			t.Errorf("%d: %q\nexpected: %q\n but got: %q", i, tc.src, tc.exp, got)
+		}
+	}
+}
+
+func TestHeadingOffset(t *testing.T) {
+	t.Parallel()
+	var parser zmk.Parser
+	parser.Initialize(input.NewInput([]byte("=== A\n==== B {-}")))
+	ast := parser.Parse()
+	sz.AssignIdentifier(ast)
+	var sb strings.Builder
+	if err := latex.NewEncoder(1).Encode(&sb, ast, ""); err != nil {
+		t.Fatal(err)
+	}
+	exp := "\\subsection{A}\\label{a}\n\n\\subsubsection{B}\\label{b}\n"
+	if got := sb.String(); got != exp {
+		t.Errorf("expected %q, but got %q", exp, got)
+	}
+}
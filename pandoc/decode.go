@@ -0,0 +1,637 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package pandoc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/text"
+	"t73f.de/r/zsx"
+)
+
+// Decode transforms the given Pandoc document into metadata and a sz block
+// list. Metadata values are reduced to their textual content.
+func Decode(doc *Document) (*meta.Meta, *sx.Pair, error) {
+	var dec decoder
+	m := meta.New(id.Invalid)
+	for key, val := range doc.Meta {
+		if meta.KeyIsValid(key) {
+			m.SetNonEmpty(key, meta.Value(strings.TrimSpace(dec.metaText(val))))
+		}
+	}
+	blocks := dec.blocks(doc.Blocks)
+	if dec.err != nil {
+		return nil, nil, dec.err
+	}
+	return m, zsx.MakeBlockList(blocks), nil
+}
+
+// decoder stores the first error that occurred while decoding.
+type decoder struct {
+	err error
+}
+
+func (dec *decoder) setError(format string, args ...any) {
+	if dec.err == nil {
+		dec.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (dec *decoder) element(v any) (string, any) {
+	switch elem := v.(type) {
+	case Element:
+		return elem.T, elem.C
+	case map[string]any:
+		if t, isString := elem["t"].(string); isString {
+			return t, elem["c"]
+		}
+	}
+	dec.setError("not a Pandoc element: %v/%T", v, v)
+	return "", nil
+}
+
+func (dec *decoder) list(v any, minLen int) []any {
+	if lst, isList := v.([]any); isList && len(lst) >= minLen {
+		return lst
+	}
+	dec.setError("not a list of at least %d elements: %v/%T", minLen, v, v)
+	return make([]any, minLen)
+}
+
+func (dec *decoder) string(v any) string {
+	if s, isString := v.(string); isString {
+		return s
+	}
+	dec.setError("not a string: %v/%T", v, v)
+	return ""
+}
+
+func (dec *decoder) int(v any) int {
+	switch num := v.(type) {
+	case float64:
+		return int(num)
+	case int:
+		return num
+	case json.Number:
+		if i, err := num.Int64(); err == nil {
+			return int(i)
+		}
+	}
+	dec.setError("not a number: %v/%T", v, v)
+	return 0
+}
+
+func (dec *decoder) metaText(v any) string {
+	t, c := dec.element(v)
+	switch t {
+	case "MetaString":
+		return dec.string(c)
+	case "MetaBool":
+		if b, isBool := c.(bool); isBool && b {
+			return meta.ValueTrue
+		}
+		return meta.ValueFalse
+	case "MetaInlines":
+		return text.EvaluateInlineString(dec.inlines(c))
+	case "MetaBlocks":
+		var sb strings.Builder
+		for obj := range dec.blocks(dec.list(c, 0)).Values() {
+			if node, isPair := sx.GetPair(obj); isPair && zsx.NodeSymbol(node) == zsx.SymPara {
+				sb.WriteByte(' ')
+				sb.WriteString(text.EvaluateInlineString(node.Tail()))
+			}
+		}
+		return sb.String()
+	case "MetaList":
+		var sb strings.Builder
+		for _, elem := range dec.list(c, 0) {
+			sb.WriteByte(' ')
+			sb.WriteString(dec.metaText(elem))
+		}
+		return sb.String()
+	}
+	return ""
+}
+
+func (dec *decoder) blocks(lst []any) *sx.Pair {
+	var lb sx.ListBuilder
+	for _, v := range lst {
+		if node := dec.block(v); node != nil {
+			lb.Add(node)
+		}
+	}
+	return lb.List()
+}
+
+func (dec *decoder) block(v any) *sx.Pair {
+	t, c := dec.element(v)
+	switch t {
+	case "Plain", "Para":
+		ins := dec.list(c, 0)
+		if len(ins) == 1 {
+			if node := dec.singleInlineBlock(ins[0]); node != nil {
+				return node
+			}
+		}
+		return zsx.MakeParaList(dec.inlines(ins))
+	case "Header":
+		args := dec.list(c, 3)
+		attrs, _ := dec.attr(args[1])
+		return zsx.MakeHeading(attrs, dec.int(args[0]), dec.inlines(args[2]))
+	case "HorizontalRule":
+		return zsx.MakeThematic(nil)
+	case "BulletList":
+		return zsx.MakeList(zsx.SymListUnordered, nil, dec.listItems(c))
+	case "OrderedList":
+		args := dec.list(c, 2)
+		return zsx.MakeList(zsx.SymListOrdered, nil, dec.listItems(args[1]))
+	case "BlockQuote":
+		blocks, ins := dec.regionContent(dec.list(c, 0))
+		return zsx.MakeRegion(zsx.SymRegionQuote, nil, blocks, ins)
+	case "DefinitionList":
+		return dec.description(c)
+	case "CodeBlock":
+		args := dec.list(c, 2)
+		attrs, kv := dec.attr(args[0])
+		sym := zsx.SymVerbatimCode
+		switch kv[KeyNode] {
+		case zsx.SymVerbatimEval.GetValue():
+			sym = zsx.SymVerbatimEval
+		case zsx.SymVerbatimZettel.GetValue():
+			sym = zsx.SymVerbatimZettel
+		}
+		return zsx.MakeVerbatim(sym, attrs, dec.string(args[1]))
+	case "RawBlock":
+		args := dec.list(c, 2)
+		switch format := dec.string(args[0]); format {
+		case "html":
+			return zsx.MakeVerbatim(zsx.SymVerbatimHTML, nil, dec.string(args[1]))
+		case formatComment:
+			return zsx.MakeVerbatim(zsx.SymVerbatimComment, nil, dec.string(args[1]))
+		default:
+			return zsx.MakeVerbatim(zsx.SymVerbatimCode, makeDefaultAttr(format), dec.string(args[1]))
+		}
+	case "LineBlock":
+		var lb sx.ListBuilder
+		for i, line := range dec.list(c, 0) {
+			if i > 0 {
+				lb.Add(sx.MakeList(zsx.SymHard))
+			}
+			lb.ExtendBang(dec.inlines(line))
+		}
+		return zsx.MakeRegion(zsx.SymRegionVerse, nil, sx.MakeList(zsx.MakeParaList(lb.List())), nil)
+	case "Div":
+		return dec.div(c)
+	case "Table":
+		return dec.table(c)
+	case "Figure":
+		args := dec.list(c, 3)
+		attrs, _ := dec.attr(args[0])
+		return zsx.MakeRegion(zsx.SymRegionBlock, attrs, dec.blocks(dec.list(args[2], 0)), nil)
+	}
+	return nil
+}
+
+// singleInlineBlock returns a block node, if the only inline element of a
+// paragraph represents a block.
+func (dec *decoder) singleInlineBlock(v any) *sx.Pair {
+	t, c := dec.element(v)
+	switch t {
+	case "Math":
+		args := dec.list(c, 2)
+		if mt, _ := dec.element(args[0]); mt == "DisplayMath" {
+			return zsx.MakeVerbatim(zsx.SymVerbatimMath, nil, dec.string(args[1]))
+		}
+	case "Image":
+		args := dec.list(c, 3)
+		attrs, kv := dec.attr(args[0])
+		if kv[KeyNode] == zsx.SymBLOB.GetValue() {
+			target := dec.list(args[2], 2)
+			if syntax, data, ok := parseDataURL(dec.string(target[0])); ok {
+				return sx.MakeList(zsx.SymBLOB, attrs, sx.MakeString(syntax), sx.MakeString(data)).
+					ExtendBang(dec.inlines(args[1]))
+			}
+		}
+	case "Link":
+		args := dec.list(c, 3)
+		attrs, kv := dec.attr(args[0])
+		if kv[KeyNode] == zsx.SymTransclude.GetValue() {
+			target := dec.list(args[2], 2)
+			ref := makeReference(dec.string(target[0]), kv[KeyRef])
+			return zsx.MakeTransclusion(attrs, ref, dec.inlines(args[1]))
+		}
+	}
+	return nil
+}
+
+func (dec *decoder) listItems(v any) *sx.Pair {
+	var lb sx.ListBuilder
+	for _, item := range dec.list(v, 0) {
+		lb.Add(zsx.MakeListItem(nil, dec.blocks(dec.list(item, 0))))
+	}
+	return lb.List()
+}
+
+func (dec *decoder) description(v any) *sx.Pair {
+	var lb sx.ListBuilder
+	lb.AddN(zsx.SymDescription, sx.Nil())
+	for _, item := range dec.list(v, 0) {
+		args := dec.list(item, 2)
+		lb.Add(zsx.MakeTerm(nil, dec.inlines(args[0])))
+		if defs := dec.list(args[1], 0); len(defs) > 0 {
+			var detail sx.ListBuilder
+			detail.Add(zsx.SymDetail)
+			for _, def := range defs {
+				detail.Add(zsx.MakeEntry(nil, dec.blocks(dec.list(def, 0))))
+			}
+			lb.Add(detail.List())
+		}
+	}
+	return lb.List()
+}
+
+func (dec *decoder) div(v any) *sx.Pair {
+	args := dec.list(v, 2)
+	attrs, kv := dec.attr(args[0])
+	content := dec.list(args[1], 0)
+	switch node := kv[KeyNode]; node {
+	case "":
+		blocks, ins := dec.regionContent(content)
+		return zsx.MakeRegion(zsx.SymRegionBlock, attrs, blocks, ins)
+	case zsx.SymRegionVerse.GetValue():
+		blocks, ins := dec.regionContent(content)
+		return zsx.MakeRegion(zsx.SymRegionVerse, attrs, blocks, ins)
+	case zsx.SymBlock.GetValue():
+		return zsx.MakeBlockList(dec.blocks(content))
+	case zsx.SymInline.GetValue():
+		return zsx.MakeParaList(dec.blockInlines(content))
+	case zsx.SymListQuote.GetValue():
+		// Each BlockQuote is an item, possibly wrapped by a Div with its attributes.
+		var items sx.ListBuilder
+		for _, quote := range content {
+			if item := dec.quoteItem(quote); item != nil {
+				items.Add(item)
+			}
+		}
+		return zsx.MakeList(zsx.SymListQuote, attrs, items.List())
+	default:
+		if len(content) != 1 {
+			dec.setError("Div for %v must contain exactly one element, but got %d", node, len(content))
+			return nil
+		}
+		return setAttrs(dec.block(content[0]), attrs)
+	}
+}
+
+// quoteItem decodes an item of a quotation list.
+func (dec *decoder) quoteItem(v any) *sx.Pair {
+	var attrs *sx.Pair
+	t, c := dec.element(v)
+	if t == "Div" {
+		args := dec.list(c, 2)
+		itemAttrs, kv := dec.attr(args[0])
+		content := dec.list(args[1], 0)
+		if kv[KeyNode] != zsx.SymListItem.GetValue() || len(content) != 1 {
+			dec.setError("Div for %v is not an item of a quotation list", kv[KeyNode])
+			return nil
+		}
+		attrs = itemAttrs
+		t, c = dec.element(content[0])
+	}
+	if t != "BlockQuote" {
+		dec.setError("BlockQuote expected, but got %v", t)
+		return nil
+	}
+	return zsx.MakeListItem(attrs, dec.blocks(dec.list(c, 0)))
+}
+
+// regionContent splits the content of a region into its blocks and the
+// inline text that was stored as the last block.
+func (dec *decoder) regionContent(content []any) (*sx.Pair, *sx.Pair) {
+	if l := len(content); l > 0 {
+		if t, c := dec.element(content[l-1]); t == "Div" {
+			args := dec.list(c, 2)
+			if _, kv := dec.attr(args[0]); kv[KeyNode] == zsx.SymInline.GetValue() {
+				return dec.blocks(content[:l-1]), dec.blockInlines(dec.list(args[1], 0))
+			}
+		}
+	}
+	return dec.blocks(content), nil
+}
+
+// blockInlines returns the inline elements of all given paragraphs, separated
+// by a hard line break.
+func (dec *decoder) blockInlines(blocks []any) *sx.Pair {
+	var lb sx.ListBuilder
+	for _, block := range blocks {
+		if t, c := dec.element(block); t == "Plain" || t == "Para" {
+			if !lb.IsEmpty() {
+				lb.Add(sx.MakeList(zsx.SymHard))
+			}
+			lb.ExtendBang(dec.inlines(c))
+		}
+	}
+	return lb.List()
+}
+
+func (dec *decoder) table(v any) *sx.Pair {
+	args := dec.list(v, 6)
+	attrs, _ := dec.attr(args[0])
+	var aligns []string
+	for _, spec := range dec.list(args[2], 0) {
+		align, _ := dec.element(dec.list(spec, 2)[0])
+		aligns = append(aligns, align)
+	}
+
+	var rows []any
+	head := dec.list(args[3], 2)
+	rows = append(rows, dec.list(head[1], 0)...)
+	var header *sx.Pair
+	if len(rows) > 0 {
+		header = dec.row(rows[0], aligns)
+		rows = rows[1:]
+	}
+	for _, body := range dec.list(args[4], 0) {
+		bodyArgs := dec.list(body, 4)
+		rows = append(rows, dec.list(bodyArgs[2], 0)...)
+		rows = append(rows, dec.list(bodyArgs[3], 0)...)
+	}
+	foot := dec.list(args[5], 2)
+	rows = append(rows, dec.list(foot[1], 0)...)
+
+	var lb sx.ListBuilder
+	lb.AddN(zsx.SymTable, attrs, header)
+	for _, row := range rows {
+		lb.Add(dec.row(row, aligns))
+	}
+	return lb.List()
+}
+
+var alignValues = map[string]sx.String{
+	"AlignLeft":   zsx.AttrAlignLeft,
+	"AlignCenter": zsx.AttrAlignCenter,
+	"AlignRight":  zsx.AttrAlignRight,
+}
+
+func (dec *decoder) row(v any, aligns []string) *sx.Pair {
+	args := dec.list(v, 2)
+	attrs, _ := dec.attr(args[0])
+	var cells sx.ListBuilder
+	for i, cell := range dec.list(args[1], 0) {
+		cellArgs := dec.list(cell, 5)
+		cellAttrs, _ := dec.attr(cellArgs[0])
+		align, _ := dec.element(cellArgs[1])
+		if align == "AlignDefault" && i < len(aligns) {
+			align = aligns[i]
+		}
+		if value, found := alignValues[align]; found {
+			cellAttrs = cellAttrs.Cons(sx.Cons(zsx.SymAttrAlign, value))
+		}
+		cells.Add(zsx.MakeCell(cellAttrs, dec.blockInlines(dec.list(cellArgs[4], 0))))
+	}
+	return zsx.MakeRow(attrs, cells.List())
+}
+
+func (dec *decoder) inlines(v any) *sx.Pair {
+	var lb sx.ListBuilder
+	var sb strings.Builder
+	for _, elem := range dec.list(v, 0) {
+		t, c := dec.element(elem)
+		switch t {
+		case "Str":
+			sb.WriteString(dec.string(c))
+			continue
+		case "Space":
+			sb.WriteByte(' ')
+			continue
+		}
+		if sb.Len() > 0 {
+			lb.Add(zsx.MakeText(sb.String()))
+			sb.Reset()
+		}
+		dec.inline(&lb, t, c)
+	}
+	if sb.Len() > 0 {
+		lb.Add(zsx.MakeText(sb.String()))
+	}
+	return lb.List()
+}
+
+var formatSymbols = map[string]*sx.Symbol{
+	"Emph":        zsx.SymFormatEmph,
+	"Strikeout":   zsx.SymFormatDelete,
+	"Strong":      zsx.SymFormatStrong,
+	"Subscript":   zsx.SymFormatSub,
+	"Superscript": zsx.SymFormatSuper,
+	"Underline":   zsx.SymFormatInsert,
+}
+
+func (dec *decoder) inline(lb *sx.ListBuilder, t string, c any) {
+	switch t {
+	case "SoftBreak":
+		lb.Add(zsx.MakeSoft())
+	case "LineBreak":
+		lb.Add(sx.MakeList(zsx.SymHard))
+	case "Emph", "Strikeout", "Strong", "Subscript", "Superscript", "Underline":
+		lb.Add(zsx.MakeFormat(formatSymbols[t], nil, dec.inlines(c)))
+	case "SmallCaps":
+		lb.Add(zsx.MakeFormat(zsx.SymFormatSpan, makeAttr("class", "smallcaps"), dec.inlines(c)))
+	case "Quoted":
+		args := dec.list(c, 2)
+		lb.Add(zsx.MakeFormat(zsx.SymFormatQuote, nil, dec.inlines(args[1])))
+	case "Cite":
+		args := dec.list(c, 2)
+		ins := dec.inlines(args[1])
+		for _, citation := range dec.list(args[0], 0) {
+			var key string
+			if cm, isMap := citation.(map[string]any); isMap {
+				key = dec.string(cm["citationId"])
+			}
+			lb.Add(zsx.MakeCite(nil, key, ins))
+			ins = nil
+		}
+	case "Code":
+		args := dec.list(c, 2)
+		attrs, kv := dec.attr(args[0])
+		sym := zsx.SymLiteralCode
+		switch kv[KeyNode] {
+		case zsx.SymLiteralInput.GetValue():
+			sym = zsx.SymLiteralInput
+		case zsx.SymLiteralOutput.GetValue():
+			sym = zsx.SymLiteralOutput
+		}
+		lb.Add(zsx.MakeLiteral(sym, attrs, dec.string(args[1])))
+	case "Math":
+		args := dec.list(c, 2)
+		lb.Add(zsx.MakeLiteral(zsx.SymLiteralMath, nil, dec.string(args[1])))
+	case "RawInline":
+		args := dec.list(c, 2)
+		if format := dec.string(args[0]); format == formatComment {
+			lb.Add(zsx.MakeLiteral(zsx.SymLiteralComment, nil, dec.string(args[1])))
+		} else {
+			lb.Add(zsx.MakeLiteral(zsx.SymLiteralCode, makeDefaultAttr(format), dec.string(args[1])))
+		}
+	case "Link":
+		args := dec.list(c, 3)
+		attrs, kv := dec.attr(args[0])
+		target := dec.list(args[2], 2)
+		if title := dec.string(target[1]); title != "" {
+			attrs = appendAttr(attrs, "title", title)
+		}
+		lb.Add(zsx.MakeLink(attrs, makeReference(dec.string(target[0]), kv[KeyRef]), dec.inlines(args[1])))
+	case "Image":
+		lb.Add(dec.image(c))
+	case "Note":
+		lb.Add(zsx.MakeEndnote(nil, dec.blockInlines(dec.list(c, 0))))
+	case "Span":
+		dec.span(lb, c)
+	}
+}
+
+func (dec *decoder) image(v any) *sx.Pair {
+	args := dec.list(v, 3)
+	attrs, kv := dec.attr(args[0])
+	target := dec.list(args[2], 2)
+	url := dec.string(target[0])
+	if title := dec.string(target[1]); title != "" {
+		attrs = appendAttr(attrs, "title", title)
+	}
+	if syntax, data, ok := parseDataURL(url); ok {
+		return sx.MakeList(zsx.SymEmbedBLOB, attrs, sx.MakeString(syntax), sx.MakeString(data)).
+			ExtendBang(dec.inlines(args[1]))
+	}
+	return zsx.MakeEmbed(attrs, makeReference(url, kv[KeyRef]), kv[KeySyntax], dec.inlines(args[1]))
+}
+
+func (dec *decoder) span(lb *sx.ListBuilder, v any) {
+	args := dec.list(v, 2)
+	attrs, kv := dec.attr(args[0])
+	if mark, isMark := kv[KeyMark]; isMark {
+		lb.Add(zsx.MakeMark(attrs, mark, dec.inlines(args[1])))
+		return
+	}
+	switch node := kv[KeyNode]; node {
+	case "":
+		lb.Add(zsx.MakeFormat(zsx.SymFormatSpan, attrs, dec.inlines(args[1])))
+	case zsx.SymFormatMark.GetValue():
+		lb.Add(zsx.MakeFormat(zsx.SymFormatMark, attrs, dec.inlines(args[1])))
+	default:
+		ins := dec.inlines(args[1])
+		if ins == nil || ins.Tail() != nil {
+			dec.setError("Span for %v must contain exactly one element, but got %v", node, ins)
+			return
+		}
+		lb.Add(setAttrs(ins.Head(), attrs))
+	}
+}
+
+// setAttrs replaces the attributes of the given node.
+func setAttrs(node *sx.Pair, attrs *sx.Pair) *sx.Pair {
+	if next := node.Tail(); next != nil {
+		next.SetCar(attrs)
+	}
+	return node
+}
+
+// attr transforms a Pandoc Attr into sz attributes. All key/value pairs with
+// zettel-specific data are returned separately.
+func (dec *decoder) attr(v any) (*sx.Pair, map[string]string) {
+	args := dec.list(v, 3)
+	kv := map[string]string{}
+	var lb sx.ListBuilder
+	if ident := dec.string(args[0]); ident != "" {
+		lb.Add(sx.Cons(zsx.SymSpecialID, sx.MakeString(ident)))
+	}
+	classes := dec.list(args[1], 0)
+	if len(classes) > 0 {
+		lb.Add(sx.Cons(sx.MakeString(zsx.DefaultAttribute), sx.MakeString(dec.string(classes[0]))))
+		if len(classes) > 1 {
+			rest := make([]string, 0, len(classes)-1)
+			for _, class := range classes[1:] {
+				rest = append(rest, dec.string(class))
+			}
+			lb.Add(sx.Cons(sx.MakeString("class"), sx.MakeString(strings.Join(rest, " "))))
+		}
+	}
+	for _, elem := range dec.list(args[2], 0) {
+		pair := dec.list(elem, 2)
+		key, val := dec.string(pair[0]), dec.string(pair[1])
+		if strings.HasPrefix(key, "zs-") {
+			kv[key] = val
+		} else {
+			lb.Add(sx.Cons(sx.MakeString(key), sx.MakeString(val)))
+		}
+	}
+	return lb.List(), kv
+}
+
+func makeAttr(key, val string) *sx.Pair {
+	return sx.MakeList(sx.Cons(sx.MakeString(key), sx.MakeString(val)))
+}
+func makeDefaultAttr(val string) *sx.Pair { return makeAttr(zsx.DefaultAttribute, val) }
+
+func appendAttr(attrs *sx.Pair, key, val string) *sx.Pair {
+	if attrs == nil {
+		return makeAttr(key, val)
+	}
+	attrs.LastPair().AppendBang(sx.Cons(sx.MakeString(key), sx.MakeString(val)))
+	return attrs
+}
+
+var refStates = map[string]*sx.Symbol{
+	sz.SymRefStateZettel.GetValue():    sz.SymRefStateZettel,
+	sz.SymRefStateFound.GetValue():     sz.SymRefStateFound,
+	sz.SymRefStateBroken.GetValue():    sz.SymRefStateBroken,
+	sz.SymRefStateBased.GetValue():     sz.SymRefStateBased,
+	sz.SymRefStateQuery.GetValue():     sz.SymRefStateQuery,
+	zsx.SymRefStateExternal.GetValue(): zsx.SymRefStateExternal,
+	zsx.SymRefStateSelf.GetValue():     zsx.SymRefStateSelf,
+	zsx.SymRefStateHosted.GetValue():   zsx.SymRefStateHosted,
+	zsx.SymRefStateInvalid.GetValue():  zsx.SymRefStateInvalid,
+}
+
+// makeReference returns a reference for the given URL. If no valid reference
+// state is given, it will be derived from the URL.
+func makeReference(url, state string) *sx.Pair {
+	if sym, found := refStates[state]; found {
+		return zsx.MakeReference(sym, url)
+	}
+	return sz.ScanReference(url)
+}
+
+func parseDataURL(url string) (string, string, bool) {
+	rest, found := strings.CutPrefix(url, "data:image/")
+	if !found {
+		return "", "", false
+	}
+	mimeSub, data, found := strings.Cut(rest, ";base64,")
+	if !found {
+		return "", "", false
+	}
+	if mimeSub == "svg+xml" {
+		svg, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", "", false
+		}
+		return meta.ValueSyntaxSVG, string(svg), true
+	}
+	return mimeSub, data, true
+}
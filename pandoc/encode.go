@@ -0,0 +1,515 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package pandoc
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsx"
+)
+
+// Encode transforms the given metadata and sz block list into a Pandoc
+// document. Both arguments may be nil.
+func Encode(m *meta.Meta, content *sx.Pair) *Document {
+	doc := &Document{
+		APIVersion: APIVersion,
+		Meta:       map[string]any{},
+	}
+	if m != nil {
+		for key, val := range m.All() {
+			doc.Meta[key] = Element{T: "MetaString", C: string(val)}
+		}
+	}
+	switch zsx.NodeSymbol(content) {
+	case zsx.SymBlock:
+		doc.Blocks = encodeBlocks(content.Tail())
+	case zsx.SymInline:
+		doc.Blocks = []any{Element{T: "Para", C: encodeInlines(content.Tail())}}
+	case nil:
+		doc.Blocks = []any{}
+	default:
+		doc.Blocks = encodeBlocks(sx.Cons(content, sx.Nil()))
+	}
+	return doc
+}
+
+func encodeBlocks(lst *sx.Pair) []any {
+	result := []any{}
+	for obj := range lst.Values() {
+		if node, isPair := sx.GetPair(obj); isPair && node != nil {
+			if elem := encodeBlock(node); elem != nil {
+				result = append(result, elem)
+			}
+		}
+	}
+	return result
+}
+
+func encodeBlock(node *sx.Pair) any {
+	sym := zsx.NodeSymbol(node)
+	switch sym {
+	case zsx.SymBlock:
+		return Element{T: "Div", C: []any{encodeAttr(nil, KeyNode, sym.GetValue()), encodeBlocks(node.Tail())}}
+	case zsx.SymPara:
+		return Element{T: "Para", C: encodeInlines(node.Tail())}
+	case zsx.SymHeading:
+		// (HEADING attrs level inline...)
+		next := node.Tail()
+		level := 1
+		if num, isInt := next.Tail().Car().(sx.Int64); isInt {
+			level = int(num)
+		}
+		return Element{T: "Header", C: []any{level, encodeAttr(next.Head()), encodeInlines(next.Tail().Tail())}}
+	case zsx.SymThematic:
+		return wrapBlock(node.Tail().Head(), sym, Element{T: "HorizontalRule"})
+	case zsx.SymListOrdered, zsx.SymListUnordered:
+		return encodeList(sym, node)
+	case zsx.SymListQuote:
+		// (QUOTATION attrs (ITEM attrs block...)...)
+		quotes := []any{}
+		for obj := range node.Tail().Tail().Values() {
+			if item, isPair := sx.GetPair(obj); isPair && item != nil {
+				attrs, elems := zsx.GetListItem(item)
+				quotes = append(quotes, wrapBlock(attrs, zsx.SymListItem, Element{T: "BlockQuote", C: encodeBlocks(elems)}))
+			}
+		}
+		return Element{T: "Div", C: []any{encodeAttr(node.Tail().Head(), KeyNode, sym.GetValue()), quotes}}
+	case zsx.SymDescription:
+		return encodeDescription(node)
+	case zsx.SymTable:
+		return encodeTable(node)
+	case zsx.SymRegionBlock:
+		// (REGION-BLOCK attrs (block...) inline...)
+		next := node.Tail()
+		return Element{T: "Div", C: []any{encodeAttr(next.Head()), encodeRegionContent(next.Tail())}}
+	case zsx.SymRegionQuote:
+		next := node.Tail()
+		return wrapBlock(next.Head(), sym, Element{T: "BlockQuote", C: encodeRegionContent(next.Tail())})
+	case zsx.SymRegionVerse:
+		next := node.Tail()
+		return Element{T: "Div", C: []any{
+			encodeAttr(next.Head(), KeyNode, sym.GetValue()),
+			encodeRegionContent(next.Tail()),
+		}}
+	case zsx.SymVerbatimCode:
+		_, attrs, content := zsx.GetLiteral(node)
+		return Element{T: "CodeBlock", C: []any{encodeAttr(attrs), content}}
+	case zsx.SymVerbatimEval, zsx.SymVerbatimZettel:
+		_, attrs, content := zsx.GetLiteral(node)
+		return Element{T: "CodeBlock", C: []any{encodeAttr(attrs, KeyNode, sym.GetValue()), content}}
+	case zsx.SymVerbatimMath:
+		_, attrs, content := zsx.GetLiteral(node)
+		math := Element{T: "Math", C: []any{Element{T: "DisplayMath"}, content}}
+		return wrapBlock(attrs, sym, Element{T: "Para", C: []any{math}})
+	case zsx.SymVerbatimHTML:
+		_, attrs, content := zsx.GetLiteral(node)
+		return wrapBlock(attrs, sym, Element{T: "RawBlock", C: []any{"html", content}})
+	case zsx.SymVerbatimComment:
+		_, attrs, content := zsx.GetLiteral(node)
+		return wrapBlock(attrs, sym, Element{T: "RawBlock", C: []any{formatComment, content}})
+	case zsx.SymBLOB:
+		// (BLOB attrs syntax data inline...)
+		next := node.Tail()
+		syntax, data := stringValue(next.Tail().Car()), stringValue(next.Tail().Tail().Car())
+		img := Element{T: "Image", C: []any{
+			encodeAttr(next.Head(), KeyNode, sym.GetValue()),
+			encodeInlines(next.Tail().Tail().Tail()),
+			[]any{dataURL(syntax, data), ""},
+		}}
+		return Element{T: "Para", C: []any{img}}
+	case zsx.SymTransclude:
+		// (TRANSCLUDE attrs reference inline...)
+		next := node.Tail()
+		url, refKV := encodeReference(next.Tail().Head())
+		link := Element{T: "Link", C: []any{
+			encodeAttr(next.Head(), append(refKV, KeyNode, sym.GetValue())...),
+			encodeInlines(next.Tail().Tail()),
+			[]any{url, ""},
+		}}
+		return Element{T: "Para", C: []any{link}}
+	}
+	return nil
+}
+
+// wrapBlock encloses the given element with a Div, if there are attributes
+// that cannot be stored in the element itself.
+func wrapBlock(attrs *sx.Pair, sym *sx.Symbol, elem Element) any {
+	if attrs == nil {
+		return elem
+	}
+	return Element{T: "Div", C: []any{encodeAttr(attrs, KeyNode, sym.GetValue()), []any{elem}}}
+}
+
+func encodeList(sym *sx.Symbol, node *sx.Pair) any {
+	// (ORDERED attrs (ITEM attrs block...)...)
+	items := []any{}
+	for obj := range node.Tail().Tail().Values() {
+		if item, isPair := sx.GetPair(obj); isPair && item != nil {
+			_, elems := zsx.GetListItem(item)
+			items = append(items, encodeBlocks(elems))
+		}
+	}
+	var elem Element
+	if sym == zsx.SymListOrdered {
+		listAttrs := []any{1, Element{T: "Decimal"}, Element{T: "Period"}}
+		elem = Element{T: "OrderedList", C: []any{listAttrs, items}}
+	} else {
+		elem = Element{T: "BulletList", C: items}
+	}
+	return wrapBlock(node.Tail().Head(), sym, elem)
+}
+
+func encodeDescription(node *sx.Pair) any {
+	// (DESCRIPTION attrs (TERM attrs inline...) (DETAIL (ENTRY attrs block...)...)...)
+	attrs, rest := zsx.GetDescription(node)
+	var entries []any
+	var term []any
+	var defs []any
+	for obj := range rest.Values() {
+		elem, isPair := sx.GetPair(obj)
+		if !isPair || elem == nil {
+			continue
+		}
+		switch zsx.NodeSymbol(elem) {
+		case zsx.SymTerm:
+			if term != nil {
+				entries = append(entries, []any{term, defs})
+			}
+			term, defs = encodeInlines(elem.Tail().Tail()), []any{}
+		case zsx.SymDetail:
+			if term == nil {
+				term, defs = []any{}, []any{}
+			}
+			for entryObj := range elem.Tail().Values() {
+				if entry, isEntry := sx.GetPair(entryObj); isEntry && entry != nil {
+					defs = append(defs, encodeBlocks(entry.Tail().Tail()))
+				}
+			}
+		}
+	}
+	if term != nil {
+		entries = append(entries, []any{term, defs})
+	}
+	if entries == nil {
+		entries = []any{}
+	}
+	return wrapBlock(attrs, zsx.SymDescription, Element{T: "DefinitionList", C: entries})
+}
+
+func encodeTable(node *sx.Pair) any {
+	// (TABLE attrs header-row row...)
+	next := node.Tail()
+	attrs := next.Head()
+	next = next.Tail()
+	numCols := 0
+	headRows := []any{}
+	if header := next.Head(); header != nil {
+		row, cols := encodeRow(header)
+		headRows = append(headRows, row)
+		numCols = cols
+	}
+	bodyRows := []any{}
+	for obj := range next.Tail().Values() {
+		if rowNode, isPair := sx.GetPair(obj); isPair && rowNode != nil {
+			row, cols := encodeRow(rowNode)
+			bodyRows = append(bodyRows, row)
+			numCols = max(numCols, cols)
+		}
+	}
+	colSpecs := make([]any, numCols)
+	for i := range colSpecs {
+		colSpecs[i] = []any{Element{T: "AlignDefault"}, Element{T: "ColWidthDefault"}}
+	}
+	emptyAttr := encodeAttr(nil)
+	return Element{T: "Table", C: []any{
+		encodeAttr(attrs),
+		[]any{nil, []any{}},
+		colSpecs,
+		[]any{emptyAttr, headRows},
+		[]any{[]any{emptyAttr, 0, []any{}, bodyRows}},
+		[]any{emptyAttr, []any{}},
+	}}
+}
+
+func encodeRow(row *sx.Pair) ([]any, int) {
+	attrs, cellList := zsx.GetRow(row)
+	cells := []any{}
+	for obj := range cellList.Values() {
+		cell, isPair := sx.GetPair(obj)
+		if !isPair || cell == nil {
+			continue
+		}
+		// (CELL attrs inline...)
+		cellAttrs := cell.Tail().Head()
+		align := "AlignDefault"
+		if alignPair := cellAttrs.Assoc(zsx.SymAttrAlign); alignPair != nil {
+			switch stringValue(alignPair.Cdr()) {
+			case zsx.AttrAlignLeft.GetValue():
+				align = "AlignLeft"
+			case zsx.AttrAlignCenter.GetValue():
+				align = "AlignCenter"
+			case zsx.AttrAlignRight.GetValue():
+				align = "AlignRight"
+			}
+		}
+		cells = append(cells, []any{
+			encodeAttr(cellAttrs),
+			Element{T: align},
+			1, 1,
+			[]any{Element{T: "Plain", C: encodeInlines(cell.Tail().Tail())}},
+		})
+	}
+	return []any{encodeAttr(attrs), cells}, len(cells)
+}
+
+// encodeRegionContent encodes the blocks and the optional inline text of a
+// region. The inline text is stored in a Div, marked as an INLINE node.
+func encodeRegionContent(next *sx.Pair) []any {
+	blocks := encodeBlocks(next.Head())
+	if ins := next.Tail(); ins != nil {
+		blocks = append(blocks, Element{T: "Div", C: []any{
+			encodeAttr(nil, KeyNode, zsx.SymInline.GetValue()),
+			[]any{Element{T: "Plain", C: encodeInlines(ins)}},
+		}})
+	}
+	return blocks
+}
+
+func encodeInlines(lst *sx.Pair) []any {
+	result := []any{}
+	for obj := range lst.Values() {
+		node, isPair := sx.GetPair(obj)
+		if !isPair || node == nil {
+			continue
+		}
+		if zsx.NodeSymbol(node) == zsx.SymText {
+			result = appendText(result, stringValue(node.Tail().Car()))
+		} else if elem := encodeInline(node); elem != nil {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+// appendText splits the given text into Pandoc Str and Space elements.
+func appendText(result []any, s string) []any {
+	for {
+		pos := strings.IndexByte(s, ' ')
+		if pos < 0 {
+			break
+		}
+		if pos > 0 {
+			result = append(result, Element{T: "Str", C: s[:pos]})
+		}
+		if len(result) == 0 || !isSpace(result[len(result)-1]) {
+			result = append(result, Element{T: "Space"})
+		}
+		s = s[pos+1:]
+	}
+	if s != "" {
+		result = append(result, Element{T: "Str", C: s})
+	}
+	return result
+}
+
+func isSpace(v any) bool {
+	elem, isElem := v.(Element)
+	return isElem && elem.T == "Space"
+}
+
+var formatElements = map[*sx.Symbol]string{
+	zsx.SymFormatDelete: "Strikeout",
+	zsx.SymFormatEmph:   "Emph",
+	zsx.SymFormatInsert: "Underline",
+	zsx.SymFormatStrong: "Strong",
+	zsx.SymFormatSub:    "Subscript",
+	zsx.SymFormatSuper:  "Superscript",
+}
+
+func encodeInline(node *sx.Pair) any {
+	sym := zsx.NodeSymbol(node)
+	switch sym {
+	case zsx.SymSoft:
+		return Element{T: "SoftBreak"}
+	case zsx.SymHard:
+		return Element{T: "LineBreak"}
+	case zsx.SymLink:
+		// (LINK attrs reference inline...)
+		next := node.Tail()
+		url, refKV := encodeReference(next.Tail().Head())
+		return Element{T: "Link", C: []any{
+			encodeAttr(next.Head(), refKV...),
+			encodeInlines(next.Tail().Tail()),
+			[]any{url, ""},
+		}}
+	case zsx.SymEmbed:
+		// (EMBED attrs reference syntax inline...)
+		next := node.Tail()
+		url, kv := encodeReference(next.Tail().Head())
+		if syntax := stringValue(next.Tail().Tail().Car()); syntax != "" {
+			kv = append(kv, KeySyntax, syntax)
+		}
+		return Element{T: "Image", C: []any{
+			encodeAttr(next.Head(), kv...),
+			encodeInlines(next.Tail().Tail().Tail()),
+			[]any{url, ""},
+		}}
+	case zsx.SymEmbedBLOB:
+		// (EMBED-BLOB attrs syntax data inline...)
+		next := node.Tail()
+		syntax, data := stringValue(next.Tail().Car()), stringValue(next.Tail().Tail().Car())
+		return Element{T: "Image", C: []any{
+			encodeAttr(next.Head()),
+			encodeInlines(next.Tail().Tail().Tail()),
+			[]any{dataURL(syntax, data), ""},
+		}}
+	case zsx.SymCite:
+		// (CITE attrs key inline...)
+		next := node.Tail()
+		citation := map[string]any{
+			"citationId":      stringValue(next.Tail().Car()),
+			"citationPrefix":  []any{},
+			"citationSuffix":  []any{},
+			"citationMode":    Element{T: "NormalCitation"},
+			"citationNoteNum": 0,
+			"citationHash":    0,
+		}
+		cite := Element{T: "Cite", C: []any{[]any{citation}, encodeInlines(next.Tail().Tail())}}
+		return wrapInline(next.Head(), sym, cite)
+	case zsx.SymMark:
+		// (MARK attrs mark inline...)
+		next := node.Tail()
+		return Element{T: "Span", C: []any{
+			encodeAttr(next.Head(), KeyMark, stringValue(next.Tail().Car())),
+			encodeInlines(next.Tail().Tail()),
+		}}
+	case zsx.SymEndnote:
+		// (ENDNOTE attrs inline...)
+		next := node.Tail()
+		note := Element{T: "Note", C: []any{Element{T: "Para", C: encodeInlines(next.Tail())}}}
+		return wrapInline(next.Head(), sym, note)
+	case zsx.SymFormatQuote:
+		next := node.Tail()
+		quoted := Element{T: "Quoted", C: []any{Element{T: "DoubleQuote"}, encodeInlines(next.Tail())}}
+		return wrapInline(next.Head(), sym, quoted)
+	case zsx.SymFormatMark:
+		next := node.Tail()
+		return Element{T: "Span", C: []any{encodeAttr(next.Head(), KeyNode, sym.GetValue()), encodeInlines(next.Tail())}}
+	case zsx.SymFormatSpan:
+		next := node.Tail()
+		return Element{T: "Span", C: []any{encodeAttr(next.Head()), encodeInlines(next.Tail())}}
+	case zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert,
+		zsx.SymFormatStrong, zsx.SymFormatSub, zsx.SymFormatSuper:
+		next := node.Tail()
+		return wrapInline(next.Head(), sym, Element{T: formatElements[sym], C: encodeInlines(next.Tail())})
+	case zsx.SymLiteralCode:
+		_, attrs, content := zsx.GetLiteral(node)
+		return Element{T: "Code", C: []any{encodeAttr(attrs), content}}
+	case zsx.SymLiteralInput, zsx.SymLiteralOutput:
+		_, attrs, content := zsx.GetLiteral(node)
+		return Element{T: "Code", C: []any{encodeAttr(attrs, KeyNode, sym.GetValue()), content}}
+	case zsx.SymLiteralMath:
+		_, attrs, content := zsx.GetLiteral(node)
+		return wrapInline(attrs, sym, Element{T: "Math", C: []any{Element{T: "InlineMath"}, content}})
+	case zsx.SymLiteralComment:
+		_, attrs, content := zsx.GetLiteral(node)
+		return wrapInline(attrs, sym, Element{T: "RawInline", C: []any{formatComment, content}})
+	}
+	return nil
+}
+
+// wrapInline encloses the given element with a Span, if there are attributes
+// that cannot be stored in the element itself.
+func wrapInline(attrs *sx.Pair, sym *sx.Symbol, elem Element) any {
+	if attrs == nil {
+		return elem
+	}
+	return Element{T: "Span", C: []any{encodeAttr(attrs, KeyNode, sym.GetValue()), []any{elem}}}
+}
+
+// encodeReference returns the URL of the given reference. The reference state
+// is returned as an additional key/value pair, if it cannot be derived from
+// the URL.
+func encodeReference(ref *sx.Pair) (string, []string) {
+	refSym, refValue := zsx.GetReference(ref)
+	if refSym == nil {
+		return "", nil
+	}
+	if scanned, _ := zsx.GetReference(sz.ScanReference(refValue)); scanned == refSym {
+		return refValue, nil
+	}
+	return refValue, []string{KeyRef, refSym.GetValue()}
+}
+
+// encodeAttr transforms sz attributes into a Pandoc Attr. The special
+// identifier becomes the Pandoc identifier, the default attribute becomes the
+// first class. Additional key/value pairs may be given in kv.
+func encodeAttr(attrs *sx.Pair, kv ...string) []any {
+	var ident, defValue, classValue string
+	hasDefault, hasClass := false, false
+	kvs := []any{}
+	for obj := range attrs.Values() {
+		p, isPair := sx.GetPair(obj)
+		if !isPair || p == nil {
+			continue
+		}
+		switch key := p.Car().(type) {
+		case *sx.Symbol:
+			if key.IsEqualSymbol(zsx.SymSpecialID) {
+				ident = zsx.GoValue(p.Cdr())
+			}
+		case sx.String:
+			val := zsx.GoValue(p.Cdr())
+			switch k := key.GetValue(); k {
+			case zsx.DefaultAttribute:
+				defValue, hasDefault = val, true
+			case "class":
+				classValue, hasClass = val, true
+			default:
+				kvs = append(kvs, []any{k, val})
+			}
+		}
+	}
+	classes := []any{}
+	if hasDefault {
+		classes = append(classes, defValue)
+		for class := range strings.FieldsSeq(classValue) {
+			classes = append(classes, class)
+		}
+	} else if hasClass {
+		kvs = append([]any{[]any{"class", classValue}}, kvs...)
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		kvs = append(kvs, []any{kv[i], kv[i+1]})
+	}
+	return []any{ident, classes, kvs}
+}
+
+func dataURL(syntax, data string) string {
+	if syntax == meta.ValueSyntaxSVG {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(data))
+	}
+	return "data:image/" + syntax + ";base64," + data
+}
+
+func stringValue(obj sx.Object) string {
+	if s, isString := sx.GetString(obj); isString {
+		return s.GetValue()
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package pandoc converts between sz and the JSON representation of the
+// Pandoc AST.
+//
+// Zettel-specific data that has no Pandoc counterpart is stored in the
+// key/value list of a Pandoc Attr, using keys with the prefix "zs-":
+// "zs-ref" contains the reference state of a link or an embedded element,
+// "zs-syntax" the syntax of an embedded element, "zs-mark" the name of a
+// mark, and "zs-node" the sz node that is represented by a Pandoc element or
+// by its enclosing Div or Span.
+package pandoc
+
+import (
+	"encoding/json"
+	"io"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/meta"
+)
+
+// APIVersion is the version of the Pandoc AST that is produced.
+var APIVersion = []int{1, 23, 1}
+
+// Document is the top-level value of a Pandoc JSON AST.
+type Document struct {
+	APIVersion []int          `json:"pandoc-api-version"`
+	Meta       map[string]any `json:"meta"`
+	Blocks     []any          `json:"blocks"`
+}
+
+// Element is a tagged Pandoc value, e.g. a block or an inline element.
+type Element struct {
+	T string `json:"t"`
+	C any    `json:"c,omitempty"`
+}
+
+// Keys of Attr key/value lists to store zettel-specific data.
+const (
+	KeyNode   = "zs-node"
+	KeyRef    = "zs-ref"
+	KeySyntax = "zs-syntax"
+	KeyMark   = "zs-mark"
+)
+
+// Raw formats used for comments.
+const formatComment = "comment"
+
+// Write the given metadata and sz block list as a Pandoc JSON document.
+func Write(w io.Writer, m *meta.Meta, content *sx.Pair) error {
+	return json.NewEncoder(w).Encode(Encode(m, content))
+}
+
+// Read a Pandoc JSON document and return its metadata and its content as a
+// sz block list.
+func Read(r io.Reader) (*meta.Meta, *sx.Pair, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	return Decode(&doc)
+}
@@ -0,0 +1,162 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package pandoc_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/meta"
+	"t73f.de/r/zsc/pandoc"
+	"t73f.de/r/zsc/sz/zmk"
+	"t73f.de/r/zsx/input"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	testcases := []string{
+		"",
+		"abc def\nghi%%\njkl",
+		"=== Heading {-}\n==== Sub",
+		"---",
+		"* a\n* b\n** c\n# d",
+		"> quote",
+		"; term\n: detail\n: more",
+		"|=h1|=h2>\n|a|b\n|<c|:d",
+		":::\nregion\n:::",
+		":::{red}\nregion\n::: cite",
+		"<<<\nquoted\n<<<",
+		"\"\"\"\nverse\n\"\"\"",
+		"```go\nx := 1\n```",
+		"~~~\nsum\n~~~",
+		"$$$\n\\sum\n$$$",
+		"@@@html\n<b>x</b>\n@@@",
+		"%%% comment\nx\n%%%",
+		"{{{00010000000000}}}",
+		"[[a|00010000000000]] [[b|https://zettelstore.de]] [[c|#frag]] [[d|query:tag:x]] [[e|//based]]",
+		"{{img|00010000000000}}",
+		"[@key text]",
+		"[!mark|text]",
+		"Text[^note] more",
+		"__e__ **s** >>i<< ~~d~~ ^^sup^^ ,,sub,, \"\"q\"\" ##m## ::span::{a=b}",
+		"__e__{-} ``code``{go} ''input'' ==output== $$math$$",
+		"%% inline comment",
+	}
+	var parser zmk.Parser
+	for i, src := range testcases {
+		parser.Initialize(input.NewInput([]byte(src)))
+		ast := parser.Parse()
+		var buf bytes.Buffer
+		if err := pandoc.Write(&buf, nil, ast); err != nil {
+			t.Errorf("%d: %q: write error: %v", i, src, err)
+			continue
+		}
+		_, got, err := pandoc.Read(&buf)
+		if err != nil {
+			t.Errorf("%d: %q: read error: %v\n%s", i, src, err, buf.String())
+			continue
+		}
+		if exp := ast.String(); got.String() != exp {
+			t.Errorf("%d: %q\nexpected: %v\n but got: %v", i, src, exp, got)
+		}
+	}
+}
+
+func TestRoundTripSz(t *testing.T) {
+	t.Parallel()
+	testcases := []string{
+		`(BLOCK (QUOTATION () (ITEM () (PARA (TEXT "a")) (PARA (TEXT "b")))))`,
+		`(BLOCK (QUOTATION () (ITEM () (PARA (TEXT "a"))) (ITEM () (PARA (TEXT "b")))))`,
+		`(BLOCK (QUOTATION (("a" . "b")) (ITEM (("c" . "d")) (PARA (TEXT "a")) (PARA (TEXT "b"))) (ITEM () (PARA (TEXT "c")))))`,
+	}
+	for i, src := range testcases {
+		val, err := sxreader.MakeReader(strings.NewReader(src)).Read()
+		if err != nil {
+			t.Errorf("%d: %q: parse error: %v", i, src, err)
+			continue
+		}
+		ast, isPair := sx.GetPair(val)
+		if !isPair {
+			t.Errorf("%d: %q: not a list", i, src)
+			continue
+		}
+		var buf bytes.Buffer
+		if err = pandoc.Write(&buf, nil, ast); err != nil {
+			t.Errorf("%d: %q: write error: %v", i, src, err)
+			continue
+		}
+		_, got, err := pandoc.Read(&buf)
+		if err != nil {
+			t.Errorf("%d: %q: read error: %v\n%s", i, src, err, buf.String())
+			continue
+		}
+		if got.String() != src {
+			t.Errorf("%d:\nexpected: %v\n but got: %v", i, src, got)
+		}
+	}
+}
+
+func TestMeta(t *testing.T) {
+	t.Parallel()
+	m := meta.New(id.Invalid)
+	m.Set(meta.KeyTitle, "A Title")
+	m.Set(meta.KeyTags, "#a #b")
+	var buf bytes.Buffer
+	if err := pandoc.Write(&buf, m, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := pandoc.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(m, true) {
+		t.Errorf("expected %v, but got %v", m.Map(), got.Map())
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src string
+		exp string
+	}{
+		{`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[]}`, "(BLOCK)"},
+		{
+			`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[{"t":"Para","c":[{"t":"Str","c":"a"},{"t":"Space"},{"t":"Emph","c":[{"t":"Str","c":"b"}]}]}]}`,
+			`(BLOCK (PARA (TEXT "a ") (FORMAT-EMPH () (TEXT "b"))))`,
+		},
+		{
+			`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[{"t":"Para","c":[{"t":"Link","c":[["",[],[]],[{"t":"Str","c":"x"}],["https://t73f.de",""]]}]}]}`,
+			`(BLOCK (PARA (LINK () (EXTERNAL "https://t73f.de") (TEXT "x"))))`,
+		},
+		{
+			`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[{"t":"LineBlock","c":[[{"t":"Str","c":"a"}],[{"t":"Str","c":"b"}]]}]}`,
+			`(BLOCK (REGION-VERSE () ((PARA (TEXT "a") (HARD) (TEXT "b")))))`,
+		},
+	}
+	for i, tc := range testcases {
+		_, got, err := pandoc.Read(strings.NewReader(tc.src))
+		if err != nil {
+			t.Errorf("%d: error: %v", i, err)
+			continue
+		}
+		if got.String() != tc.exp {
+			t.Errorf("%d: expected %v, but got %v", i, tc.exp, got)
+		}
+	}
+}
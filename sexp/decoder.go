@@ -0,0 +1,335 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+)
+
+// ListDecoder reads the elements of a list one at a time, without reading the
+// whole list into memory. It is intended for large API responses, like the
+// result of a query.
+//
+// Elements that are lists may be entered too, so that the decoder is able to
+// stream a list that is nested within another list.
+type ListDecoder struct {
+	rd      *bufio.Reader
+	pos     Position
+	limit   int
+	depth   int // Number of entered, but not yet finished lists
+	started bool
+	buf     bytes.Buffer
+}
+
+// Position specifies a position within the input of a [ListDecoder].
+type Position struct {
+	Offset int64 // Byte offset, starting with 0
+	Line   int   // Line number, starting with 1
+	Column int   // Column in bytes, starting with 1
+}
+
+func (p Position) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Column) }
+
+// DecodeError is returned, if the list decoder detects an error.
+type DecodeError struct {
+	Pos Position
+	Err error
+}
+
+func (e *DecodeError) Error() string { return "sexp: " + e.Pos.String() + ": " + e.Err.Error() }
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// Errors of a ListDecoder.
+var (
+	ErrNoList          = errors.New("no list")
+	ErrElementTooLarge = errors.New("element too large")
+)
+
+// DefaultElementLimit is the maximum size of a list element, in bytes.
+const DefaultElementLimit = 16 << 20
+
+// NewListDecoder creates a new list decoder for the given reader.
+func NewListDecoder(r io.Reader) *ListDecoder {
+	return &ListDecoder{
+		rd:    bufio.NewReader(r),
+		pos:   Position{Line: 1, Column: 1},
+		limit: DefaultElementLimit,
+	}
+}
+
+// SetElementLimit sets the maximum size of a list element, in bytes. A value
+// less or equal to zero disables the limit.
+func (d *ListDecoder) SetElementLimit(limit int) *ListDecoder {
+	d.limit = limit
+	return d
+}
+
+// Pos returns the current position of the decoder.
+func (d *ListDecoder) Pos() Position { return d.pos }
+
+// Enter reads the opening parenthesis of the next element, which must be a
+// list. Subsequent calls to [ListDecoder.Next] return the elements of this
+// list.
+func (d *ListDecoder) Enter() error {
+	if d.started && d.depth == 0 {
+		return d.error(ErrNoList)
+	}
+	ch, err := d.skipSpace()
+	if err != nil {
+		return d.error(err)
+	}
+	if ch != '(' {
+		return d.error(ErrNoList)
+	}
+	_, _ = d.readByte()
+	d.started = true
+	d.depth++
+	return nil
+}
+
+// Next returns the next element of the innermost entered list. After its last
+// element, it returns io.EOF and continues with the enclosing list. If no list
+// was entered before, the top-level list is entered first.
+func (d *ListDecoder) Next() (sx.Object, error) {
+	if !d.started {
+		if err := d.Enter(); err != nil {
+			return nil, err
+		}
+	}
+	if d.depth == 0 {
+		return nil, io.EOF
+	}
+	ch, err := d.skipSpace()
+	if err != nil {
+		return nil, d.error(err)
+	}
+	if ch == ')' {
+		_, _ = d.readByte()
+		d.depth--
+		return nil, io.EOF
+	}
+
+	start := d.pos
+	d.buf.Reset()
+	if err = d.scanElement(); err != nil {
+		return nil, &DecodeError{Pos: start, Err: err}
+	}
+	obj, err := sxreader.MakeReader(bytes.NewReader(d.buf.Bytes())).Read()
+	if err != nil {
+		return nil, &DecodeError{Pos: start, Err: err}
+	}
+	return obj, nil
+}
+
+// All returns an iterator over all remaining elements of the list. It stops
+// after the first error.
+func (d *ListDecoder) All() iter.Seq2[sx.Object, error] {
+	return func(yield func(sx.Object, error) bool) {
+		for {
+			obj, err := d.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(obj, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (d *ListDecoder) error(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return &DecodeError{Pos: d.pos, Err: err}
+}
+
+// skipSpace skips white space and comments and returns the next byte, without
+// consuming it.
+func (d *ListDecoder) skipSpace() (byte, error) {
+	for {
+		b, err := d.rd.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch ch := b[0]; ch {
+		case ' ', '\t', '\n', '\r', '\f', '\v':
+			_, _ = d.readByte()
+		case ';':
+			if err = d.skipComment(); err != nil {
+				return 0, err
+			}
+		default:
+			return ch, nil
+		}
+	}
+}
+
+// scanElement copies the bytes of the next element into the buffer.
+func (d *ListDecoder) scanElement() error {
+	depth := 0
+	for {
+		b, err := d.rd.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				if depth == 0 && d.buf.Len() > 0 {
+					return nil // atom at the end of the input
+				}
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		ch := b[0]
+		if depth == 0 && d.atomComplete() && isDelimiter(ch) {
+			return nil
+		}
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return nil // a quote sign without an object is left to the reader
+			}
+			depth--
+			if err = d.copyByte(); err != nil {
+				return err
+			}
+			if depth == 0 {
+				return nil
+			}
+			continue
+		case '"':
+			if err = d.scanString(); err != nil {
+				return err
+			}
+			if depth == 0 {
+				return nil
+			}
+			continue
+		case ';':
+			if err = d.skipComment(); err != nil {
+				return io.ErrUnexpectedEOF
+			}
+			if err = d.writeByte(' '); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = d.copyByte(); err != nil {
+			return err
+		}
+	}
+}
+
+// atomComplete returns true, if the buffer contains an atom that is not
+// just a quote prefix.
+func (d *ListDecoder) atomComplete() bool {
+	if l := d.buf.Len(); l > 0 {
+		return !isQuote(d.buf.Bytes()[l-1])
+	}
+	return false
+}
+
+func isDelimiter(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\n', '\r', '\f', '\v', '(', ')', '"', ';':
+		return true
+	}
+	return false
+}
+
+// isQuote returns true, if the byte is a prefix of the following element.
+func isQuote(ch byte) bool { return ch == '\'' || ch == '`' || ch == ',' || ch == '@' }
+
+func (d *ListDecoder) scanString() error {
+	if err := d.copyByte(); err != nil { // opening quote
+		return err
+	}
+	for {
+		b, err := d.rd.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		ch := b[0]
+		if err = d.copyByte(); err != nil {
+			return err
+		}
+		switch ch {
+		case '"':
+			return nil
+		case '\\':
+			if _, err = d.rd.Peek(1); err != nil {
+				return io.ErrUnexpectedEOF
+			}
+			if err = d.copyByte(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// skipComment skips all bytes up to and including the next end of line.
+func (d *ListDecoder) skipComment() error {
+	for {
+		ch, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		if ch == '\n' {
+			return nil
+		}
+	}
+}
+
+func (d *ListDecoder) copyByte() error {
+	ch, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	return d.writeByte(ch)
+}
+
+func (d *ListDecoder) writeByte(ch byte) error {
+	if d.limit > 0 && d.buf.Len() >= d.limit {
+		return ErrElementTooLarge
+	}
+	return d.buf.WriteByte(ch)
+}
+
+func (d *ListDecoder) readByte() (byte, error) {
+	ch, err := d.rd.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	d.pos.Offset++
+	if ch == '\n' {
+		d.pos.Line++
+		d.pos.Column = 1
+	} else {
+		d.pos.Column++
+	}
+	return ch, nil
+}
@@ -0,0 +1,172 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/sexp"
+)
+
+func TestListDecoder(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src string
+		exp []string
+	}{
+		{"()", nil},
+		{" ( ) ", nil},
+		{"(a)", []string{"a"}},
+		{"(a b c)", []string{"a", "b", "c"}},
+		{`("a" "b)" "c\"(")`, []string{`"a"`, `"b)"`, `"c\"("`}},
+		{"(1 (2 3) ((4)) 5)", []string{"1", "(2 3)", "((4))", "5"}},
+		{"(a;comment)\n b)", []string{"a", "b"}},
+		{"((a ; comment\n b))", []string{"(a b)"}},
+		{"; comment\n(a)", []string{"a"}},
+		{`(a"b"(c))`, []string{"a", `"b"`, "(c)"}},
+		{"(zettel (id \"00001\") (rights 6))", []string{"zettel", `(id "00001")`, "(rights 6)"}},
+	}
+	for i, tc := range testcases {
+		dec := sexp.NewListDecoder(strings.NewReader(tc.src))
+		var got []string
+		for obj, err := range dec.All() {
+			if err != nil {
+				t.Errorf("%d: unexpected error: %v", i, err)
+				break
+			}
+			got = append(got, obj.String())
+		}
+		if len(got) != len(tc.exp) {
+			t.Errorf("%d: %q expected %v, but got %v", i, tc.src, tc.exp, got)
+			continue
+		}
+		for j, s := range got {
+			if s != tc.exp[j] {
+				t.Errorf("%d/%d: %q expected %q, but got %q", i, j, tc.src, tc.exp[j], s)
+			}
+		}
+		if _, err := dec.Next(); err != io.EOF {
+			t.Errorf("%d: expected EOF after list, but got %v", i, err)
+		}
+	}
+}
+
+func TestListDecoderEnter(t *testing.T) {
+	t.Parallel()
+	src := `(meta-list (query "q") (list (zettel 1) (zettel 2)) after)`
+	dec := sexp.NewListDecoder(strings.NewReader(src))
+	expect := func(exp string) {
+		t.Helper()
+		obj, err := dec.Next()
+		if err != nil {
+			t.Fatalf("expected %q, but got error %v", exp, err)
+		}
+		if got := obj.String(); got != exp {
+			t.Errorf("expected %q, but got %q", exp, got)
+		}
+	}
+	expect("meta-list")
+	expect(`(query "q")`)
+	if err := dec.Enter(); err != nil {
+		t.Fatal(err)
+	}
+	expect("list")
+	expect("(zettel 1)")
+	expect("(zettel 2)")
+	if _, err := dec.Next(); err != io.EOF {
+		t.Fatalf("expected EOF of inner list, but got %v", err)
+	}
+	expect("after")
+	if _, err := dec.Next(); err != io.EOF {
+		t.Fatalf("expected EOF of outer list, but got %v", err)
+	}
+	if err := dec.Enter(); !errors.Is(err, sexp.ErrNoList) {
+		t.Errorf("expected ErrNoList after end of list, but got %v", err)
+	}
+}
+
+func TestListDecoderErrors(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src   string
+		limit int
+		err   error
+		line  int
+		col   int
+	}{
+		{"", 0, io.ErrUnexpectedEOF, 1, 1},
+		{"  a", 0, sexp.ErrNoList, 1, 3},
+		{"(a b", 0, io.ErrUnexpectedEOF, 1, 5},
+		{"(a\n (b c", 0, io.ErrUnexpectedEOF, 2, 2},
+		{"(a\n  \"abc", 0, io.ErrUnexpectedEOF, 2, 3},
+		{"(a (b c d) e)", 4, sexp.ErrElementTooLarge, 1, 4},
+		{"(a\n  \"0123456789\")", 8, sexp.ErrElementTooLarge, 2, 3},
+	}
+	for i, tc := range testcases {
+		dec := sexp.NewListDecoder(strings.NewReader(tc.src))
+		if tc.limit > 0 {
+			dec.SetElementLimit(tc.limit)
+		}
+		var err error
+		for _, err = range dec.All() {
+		}
+		if err == nil {
+			_, err = dec.Next()
+		}
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%d: %q expected error %v, but got %v", i, tc.src, tc.err, err)
+			continue
+		}
+		var de *sexp.DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("%d: %q expected a DecodeError, but got %T", i, tc.src, err)
+			continue
+		}
+		if de.Pos.Line != tc.line || de.Pos.Column != tc.col {
+			t.Errorf("%d: %q expected position %d:%d, but got %v", i, tc.src, tc.line, tc.col, de.Pos)
+		}
+	}
+}
+
+func TestListDecoderLargeList(t *testing.T) {
+	t.Parallel()
+	const count = 100000
+	var sb strings.Builder
+	sb.WriteString("(list")
+	for range count {
+		sb.WriteString(` (zettel "20260101000000")`)
+	}
+	sb.WriteString(")")
+	dec := sexp.NewListDecoder(strings.NewReader(sb.String()))
+	if _, err := dec.Next(); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for obj, err := range dec.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pair, isPair := sx.GetPair(obj); !isPair || pair.Length() != 2 {
+			t.Fatalf("%d: expected zettel list, but got %v", n, obj)
+		}
+		n++
+	}
+	if n != count {
+		t.Errorf("expected %d elements, but got %d", count, n)
+	}
+}
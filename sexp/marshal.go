@@ -0,0 +1,536 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/sx"
+)
+
+// Marshaler is implemented by types that encode themselves as a sx object.
+type Marshaler interface {
+	MarshalSx() (sx.Object, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from a sx
+// object.
+type Unmarshaler interface {
+	UnmarshalSx(sx.Object) error
+}
+
+// PathError is returned by [Marshal] and [Unmarshal]. Path specifies the
+// element that could not be processed, e.g. "meta-list[3].rights".
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	if e.Path == "" {
+		return "sexp: " + e.Err.Error()
+	}
+	return "sexp: " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error { return e.Err }
+
+// Marshal encodes the given value as a sx object.
+//
+// Strings are encoded as sx strings, integer values as sx.Int64, booleans as
+// nil / non-nil, slices and arrays as lists, and maps with string keys as
+// association lists of the form ((key value) ...), sorted by key. A nil
+// pointer is encoded as nil. Values of type sx.Object are used as they are.
+//
+// A struct is encoded as a list of its exported fields, in the order of their
+// declaration. The encoding of a field can be changed by a struct tag with
+// key "sx". The tag consists of a name, optionally followed by comma-separated
+// options. The name is used in error messages and association lists; if it is
+// empty, the lower-case field name is used. A name of "-" ignores the field.
+// A blank field "_" with a name results in a symbol of that name, e.g. to
+// mark the type of the list. The following options are supported:
+//
+//   - symbol: a string is encoded as a symbol.
+//   - head: the value is preceded by a symbol of the field name. Lists,
+//     structs and maps are extended, other values become a list of two
+//     elements, e.g. (query "value").
+//   - rest: the elements of a slice are appended to the list of the struct.
+//     It must be the last field.
+//   - alist: the fields of a struct are encoded as an association list
+//     ((name value) ...) instead of by position.
+func Marshal(v any) (sx.Object, error) {
+	return marshalValue("", reflect.ValueOf(v), fieldOptions{})
+}
+
+// Unmarshal decodes the sx object into the value pointed to by v, in the way
+// specified by [Marshal]. Unknown keys of association lists are ignored.
+func Unmarshal(obj sx.Object, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &PathError{Err: fmt.Errorf("non-nil pointer expected, but got %T", v)}
+	}
+	return unmarshalValue("", obj, rv.Elem(), fieldOptions{})
+}
+
+// fieldOptions are the options given by a struct tag.
+type fieldOptions struct {
+	name   string
+	symbol bool
+	head   bool
+	rest   bool
+	alist  bool
+}
+
+type structField struct {
+	index int
+	blank bool
+	opts  fieldOptions
+}
+
+func structFields(t reflect.Type) []structField {
+	var result []structField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("sx")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		blank := f.Name == "_"
+		if blank && (!hasTag || name == "") {
+			continue
+		}
+		if !blank && !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		opts := fieldOptions{name: name}
+		for opt := range strings.SplitSeq(options, ",") {
+			switch opt {
+			case "symbol":
+				opts.symbol = true
+			case "head":
+				opts.head = true
+			case "rest":
+				opts.rest = true
+			case "alist":
+				opts.alist = true
+			}
+		}
+		result = append(result, structField{index: i, blank: blank, opts: opts})
+	}
+	return result
+}
+
+var (
+	typeObject      = reflect.TypeFor[sx.Object]()
+	typeMarshaler   = reflect.TypeFor[Marshaler]()
+	typeUnmarshaler = reflect.TypeFor[Unmarshaler]()
+)
+
+// ----- Marshal
+
+func marshalValue(path string, v reflect.Value, opts fieldOptions) (sx.Object, error) {
+	obj, err := marshalPlain(path, v, opts)
+	if err != nil || !opts.head {
+		return obj, err
+	}
+	sym := sx.MakeSymbol(opts.name)
+	if lst, isPair := sx.GetPair(obj); isPair && isListType(v.Type()) {
+		return lst.Cons(sym), nil
+	}
+	return sx.MakeList(sym, obj), nil
+}
+
+func marshalPlain(path string, v reflect.Value, opts fieldOptions) (sx.Object, error) {
+	if !v.IsValid() {
+		return sx.Nil(), nil
+	}
+	if v.Type() == typeObject {
+		if v.IsNil() {
+			return sx.Nil(), nil
+		}
+		return v.Interface().(sx.Object), nil
+	}
+	if v.Type().Implements(typeMarshaler) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return sx.Nil(), nil
+		}
+		obj, err := v.Interface().(Marshaler).MarshalSx()
+		if err != nil {
+			return nil, &PathError{Path: path, Err: err}
+		}
+		return obj, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if opts.symbol {
+			return sx.MakeSymbol(v.String()), nil
+		}
+		return sx.MakeString(v.String()), nil
+	case reflect.Bool:
+		return sx.MakeBoolean(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sx.Int64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return nil, &PathError{Path: path, Err: fmt.Errorf("value %d too large", u)}
+		}
+		return sx.Int64(u), nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return sx.Nil(), nil
+		}
+		return marshalPlain(path, v.Elem(), opts)
+	case reflect.Slice, reflect.Array:
+		var lb sx.ListBuilder
+		for i := range v.Len() {
+			obj, err := marshalValue(indexPath(path, i), v.Index(i), fieldOptions{symbol: opts.symbol})
+			if err != nil {
+				return nil, err
+			}
+			lb.Add(obj)
+		}
+		return lb.List(), nil
+	case reflect.Map:
+		return marshalMap(path, v)
+	case reflect.Struct:
+		return marshalStruct(path, v, opts.alist)
+	}
+	return nil, &PathError{Path: path, Err: fmt.Errorf("unsupported type %v", v.Type())}
+}
+
+func marshalMap(path string, v reflect.Value) (sx.Object, error) {
+	if v.Type().Key().Kind() != reflect.String {
+		return nil, &PathError{Path: path, Err: fmt.Errorf("unsupported map key type %v", v.Type().Key())}
+	}
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+	var lb sx.ListBuilder
+	for _, key := range keys {
+		obj, err := marshalValue(keyPath(path, key.String()), v.MapIndex(key), fieldOptions{})
+		if err != nil {
+			return nil, err
+		}
+		lb.Add(sx.MakeList(sx.MakeSymbol(key.String()), obj))
+	}
+	return lb.List(), nil
+}
+
+func marshalStruct(path string, v reflect.Value, alist bool) (sx.Object, error) {
+	var lb sx.ListBuilder
+	for _, sf := range structFields(v.Type()) {
+		if sf.blank {
+			lb.Add(sx.MakeSymbol(sf.opts.name))
+			continue
+		}
+		fpath := keyPath(path, sf.opts.name)
+		fv := v.Field(sf.index)
+		if sf.opts.rest {
+			if fv.Kind() != reflect.Slice {
+				return nil, &PathError{Path: fpath, Err: errors.New("option rest requires a slice")}
+			}
+			for i := range fv.Len() {
+				obj, err := marshalValue(indexPath(fpath, i), fv.Index(i), fieldOptions{symbol: sf.opts.symbol})
+				if err != nil {
+					return nil, err
+				}
+				lb.Add(obj)
+			}
+			continue
+		}
+		obj, err := marshalValue(fpath, fv, sf.opts)
+		if err != nil {
+			return nil, err
+		}
+		if alist {
+			obj = sx.MakeList(sx.MakeSymbol(sf.opts.name), obj)
+		}
+		lb.Add(obj)
+	}
+	return lb.List(), nil
+}
+
+// ----- Unmarshal
+
+func unmarshalValue(path string, obj sx.Object, v reflect.Value, opts fieldOptions) error {
+	if !opts.head {
+		return unmarshalPlain(path, obj, v, opts)
+	}
+	lst, isPair := sx.GetPair(obj)
+	if !isPair || lst == nil {
+		return &PathError{Path: path, Err: fmt.Errorf("list starting with %q expected, but got %v", opts.name, obj)}
+	}
+	if err := CheckSymbol(lst.Car(), sx.MakeSymbol(opts.name)); err != nil {
+		return &PathError{Path: path, Err: err}
+	}
+	if isListType(v.Type()) {
+		return unmarshalPlain(path, lst.Tail(), v, opts)
+	}
+	rest := lst.Tail()
+	if rest == nil || rest.Tail() != nil {
+		return &PathError{Path: path, Err: fmt.Errorf("list of two elements expected, but got %v", obj)}
+	}
+	return unmarshalPlain(path, rest.Car(), v, opts)
+}
+
+func isListType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == typeObject || t.Implements(typeMarshaler) || reflect.PointerTo(t).Implements(typeUnmarshaler) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return true
+	}
+	return false
+}
+
+func unmarshalPlain(path string, obj sx.Object, v reflect.Value, opts fieldOptions) error {
+	if v.Type() == typeObject {
+		if obj == nil {
+			obj = sx.Nil()
+		}
+		v.Set(reflect.ValueOf(obj))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(typeUnmarshaler) {
+		if err := v.Addr().Interface().(Unmarshaler).UnmarshalSx(obj); err != nil {
+			return &PathError{Path: path, Err: err}
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if opts.symbol {
+			sym, isSymbol := sx.GetSymbol(obj)
+			if !isSymbol {
+				return &PathError{Path: path, Err: fmt.Errorf("symbol expected, but got %v", obj)}
+			}
+			v.SetString(sym.GetValue())
+			return nil
+		}
+		s, isString := sx.GetString(obj)
+		if !isString {
+			return &PathError{Path: path, Err: fmt.Errorf("string expected, but got %v", obj)}
+		}
+		v.SetString(s.GetValue())
+		return nil
+	case reflect.Bool:
+		v.SetBool(!sx.IsNil(obj))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, isInt := obj.(sx.Int64)
+		if !isInt {
+			return &PathError{Path: path, Err: fmt.Errorf("integer expected, but got %v", obj)}
+		}
+		if v.OverflowInt(int64(i)) {
+			return &PathError{Path: path, Err: fmt.Errorf("integer %d overflows %v", i, v.Type())}
+		}
+		v.SetInt(int64(i))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, isInt := obj.(sx.Int64)
+		if !isInt {
+			return &PathError{Path: path, Err: fmt.Errorf("integer expected, but got %v", obj)}
+		}
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return &PathError{Path: path, Err: fmt.Errorf("integer %d overflows %v", i, v.Type())}
+		}
+		v.SetUint(uint64(i))
+		return nil
+	case reflect.Pointer:
+		if sx.IsNil(obj) {
+			v.SetZero()
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := unmarshalPlain(path, obj, elem.Elem(), opts); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		lst, err := getList(path, obj)
+		if err != nil {
+			return err
+		}
+		return unmarshalSlice(path, lst, v, opts)
+	case reflect.Array:
+		lst, err := getList(path, obj)
+		if err != nil {
+			return err
+		}
+		i := 0
+		for elem := range lst.Values() {
+			if i >= v.Len() {
+				return &PathError{Path: path, Err: fmt.Errorf("more than %d elements", v.Len())}
+			}
+			if err = unmarshalValue(indexPath(path, i), elem, v.Index(i), fieldOptions{symbol: opts.symbol}); err != nil {
+				return err
+			}
+			i++
+		}
+		return nil
+	case reflect.Map:
+		lst, err := getList(path, obj)
+		if err != nil {
+			return err
+		}
+		return unmarshalMap(path, lst, v)
+	case reflect.Struct:
+		lst, err := getList(path, obj)
+		if err != nil {
+			return err
+		}
+		if opts.alist {
+			return unmarshalAlist(path, lst, v)
+		}
+		return unmarshalStruct(path, lst, v)
+	}
+	return &PathError{Path: path, Err: fmt.Errorf("unsupported type %v", v.Type())}
+}
+
+func getList(path string, obj sx.Object) (*sx.Pair, error) {
+	lst, isPair := sx.GetPair(obj)
+	if !isPair {
+		return nil, &PathError{Path: path, Err: fmt.Errorf("list expected, but got %v", obj)}
+	}
+	for node := lst; node != nil; {
+		next, isNextPair := sx.GetPair(node.Cdr())
+		if !isNextPair {
+			return nil, &PathError{Path: path, Err: sx.ErrImproper{Pair: lst}}
+		}
+		node = next
+	}
+	return lst, nil
+}
+
+func unmarshalSlice(path string, lst *sx.Pair, v reflect.Value, opts fieldOptions) error {
+	result := reflect.MakeSlice(v.Type(), 0, lst.Length())
+	i := 0
+	for elem := range lst.Values() {
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := unmarshalValue(indexPath(path, i), elem, ev, fieldOptions{symbol: opts.symbol}); err != nil {
+			return err
+		}
+		result = reflect.Append(result, ev)
+		i++
+	}
+	v.Set(result)
+	return nil
+}
+
+func unmarshalMap(path string, lst *sx.Pair, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return &PathError{Path: path, Err: fmt.Errorf("unsupported map key type %v", v.Type().Key())}
+	}
+	result := reflect.MakeMap(v.Type())
+	for i := 0; lst != nil; i, lst = i+1, lst.Tail() {
+		key, val, err := getAssoc(indexPath(path, i), lst.Car())
+		if err != nil {
+			return err
+		}
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err = unmarshalPlain(keyPath(path, key), val, ev, fieldOptions{}); err != nil {
+			return err
+		}
+		result.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+	}
+	v.Set(result)
+	return nil
+}
+
+// getAssoc returns key and value of an element (key value) of an association
+// list.
+func getAssoc(path string, obj sx.Object) (string, sx.Object, error) {
+	vals, err := ParseList(obj, "yo")
+	if err != nil {
+		return "", nil, &PathError{Path: path, Err: err}
+	}
+	return vals[0].(*sx.Symbol).GetValue(), vals[1], nil
+}
+
+func unmarshalStruct(path string, lst *sx.Pair, v reflect.Value) error {
+	for _, sf := range structFields(v.Type()) {
+		fpath := keyPath(path, sf.opts.name)
+		if sf.opts.rest {
+			fv := v.Field(sf.index)
+			if fv.Kind() != reflect.Slice {
+				return &PathError{Path: fpath, Err: errors.New("option rest requires a slice")}
+			}
+			if err := unmarshalSlice(fpath, lst, fv, sf.opts); err != nil {
+				return err
+			}
+			lst = nil
+			break
+		}
+		if lst == nil {
+			return &PathError{Path: fpath, Err: ErrElementsMissing}
+		}
+		if sf.blank {
+			if err := CheckSymbol(lst.Car(), sx.MakeSymbol(sf.opts.name)); err != nil {
+				return &PathError{Path: path, Err: err}
+			}
+		} else if err := unmarshalValue(fpath, lst.Car(), v.Field(sf.index), sf.opts); err != nil {
+			return err
+		}
+		lst = lst.Tail()
+	}
+	if lst != nil {
+		return &PathError{Path: path, Err: ErrNoSpec}
+	}
+	return nil
+}
+
+func unmarshalAlist(path string, lst *sx.Pair, v reflect.Value) error {
+	fields := structFields(v.Type())
+	for i := 0; lst != nil; i, lst = i+1, lst.Tail() {
+		key, val, err := getAssoc(indexPath(path, i), lst.Car())
+		if err != nil {
+			return err
+		}
+		for _, sf := range fields {
+			if !sf.blank && sf.opts.name == key {
+				if err = unmarshalValue(keyPath(path, key), val, v.Field(sf.index), sf.opts); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func indexPath(path string, i int) string { return path + "[" + strconv.Itoa(i) + "]" }
+
+func keyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,156 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp_test
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/sexp"
+	"t73f.de/r/zsc/webapi"
+)
+
+type testEntry struct {
+	_      struct{}          `sx:"zettel"`
+	ID     string            `sx:"id"`
+	Meta   map[string]string `sx:"meta,head"`
+	Rights sexp.Rights       `sx:"rights"`
+}
+
+type testConfig struct {
+	Name    string `sx:"name"`
+	Level   int    `sx:"level"`
+	Enabled bool   `sx:"enabled"`
+}
+
+type testList struct {
+	Kind    string      `sx:"kind,symbol"`
+	Query   string      `sx:"query,head"`
+	Config  testConfig  `sx:"config,head,alist"`
+	Tags    []string    `sx:"tags,head,symbol"`
+	Raw     sx.Object   `sx:"raw"`
+	Ignored string      `sx:"-"`
+	Entries []testEntry `sx:"meta-list,rest"`
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	src := `(meta-list (query "tags:#zettel") (config (name "n") (level 3) (enabled T)) (tags a b) (1 2)
+ (zettel "00010000000000" (meta (title "Home") (role "zettel")) (rights read))
+ (zettel "00000000000001" (meta) (rights create update delete)))`
+	obj, err := sxreader.MakeReader(strings.NewReader(src)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got testList
+	if err = sexp.Unmarshal(obj, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Kind != "meta-list" || got.Query != "tags:#zettel" || !slices.Equal(got.Tags, []string{"a", "b"}) {
+		t.Errorf("unexpected header: %q %q %v", got.Kind, got.Query, got.Tags)
+	}
+	if exp := (testConfig{Name: "n", Level: 3, Enabled: true}); got.Config != exp {
+		t.Errorf("config: expected %v, but got %v", exp, got.Config)
+	}
+	if !got.Raw.IsEqual(sx.MakeList(sx.Int64(1), sx.Int64(2))) {
+		t.Errorf("raw: unexpected %v", got.Raw)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("expected 2 entries, but got %v", got.Entries)
+	}
+	if e := got.Entries[0]; e.ID != "00010000000000" || e.Meta["title"] != "Home" || webapi.ZettelRights(e.Rights) != webapi.ZettelCanRead {
+		t.Errorf("entry 0: unexpected %v", e)
+	}
+	if e := got.Entries[1]; len(e.Meta) != 0 || webapi.ZettelRights(e.Rights) != webapi.ZettelCanCreate|webapi.ZettelCanWrite|webapi.ZettelCanDelete {
+		t.Errorf("entry 1: unexpected %v", e)
+	}
+
+	enc, err := sexp.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again testList
+	if err = sexp.Unmarshal(enc, &again); err != nil {
+		t.Fatalf("unmarshal of %v: %v", enc, err)
+	}
+	if again.Config != got.Config || len(again.Entries) != 2 || !maps.Equal(again.Entries[0].Meta, got.Entries[0].Meta) {
+		t.Errorf("round trip failed: %v", enc)
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src  string
+		path string
+	}{
+		{`(meta-list (query 1) (config) (tags) ())`, "query"},
+		{`(meta-list (query "") (config (level "3")) (tags) ())`, "config.level"},
+		{`(meta-list (query "") (config) (tags "a") ())`, "tags[0]"},
+		{`(meta-list (query "") (config) (tags) () (zettel "1" (meta) (rights)) (zettel "2" (meta) rights))`, "meta-list[1].rights"},
+		{`(meta-list (query "") (config) (tags) () (zettel "1" (meta (title 1)) (rights)))`, "meta-list[0].meta.title"},
+		{`(meta-list (query "") (config) (tags) () (zettel "1" (meta)))`, "meta-list[0].rights"},
+		{`(meta-list (query "") (config))`, "tags"},
+	}
+	for i, tc := range testcases {
+		obj, err := sxreader.MakeReader(strings.NewReader(tc.src)).Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got testList
+		err = sexp.Unmarshal(obj, &got)
+		var pe *sexp.PathError
+		if !errors.As(err, &pe) {
+			t.Errorf("%d: expected path error, but got %v", i, err)
+			continue
+		}
+		if pe.Path != tc.path {
+			t.Errorf("%d: expected path %q, but got %q (%v)", i, tc.path, pe.Path, err)
+		}
+	}
+
+	var i8 struct{ Value int8 }
+	if err := sexp.Unmarshal(sx.MakeList(sx.Int64(1000)), &i8); err == nil {
+		t.Error("overflow must result in an error")
+	}
+	if err := sexp.Unmarshal(sx.Nil(), i8); err == nil {
+		t.Error("non-pointer must result in an error")
+	}
+}
+
+func TestParseEncodeZettel(t *testing.T) {
+	t.Parallel()
+	zd := webapi.ZettelData{
+		Meta:     webapi.ZettelMeta{"title": "Title", "role": "zettel"},
+		Rights:   webapi.ZettelCanRead | webapi.ZettelCanWrite,
+		Encoding: "",
+		Content:  "Some content",
+	}
+	got, err := sexp.ParseZettel(sexp.EncodeZettel(zd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got.Meta, zd.Meta) || got.Rights != zd.Rights || got.Encoding != zd.Encoding || got.Content != zd.Content {
+		t.Errorf("expected %v, but got %v", zd, got)
+	}
+	if _, err = sexp.ParseZettel(sx.MakeList(sexp.SymZettel)); err == nil {
+		t.Error("incomplete zettel must result in an error")
+	}
+}
@@ -0,0 +1,149 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp
+
+import (
+	"t73f.de/r/sx"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/webapi"
+	"t73f.de/r/zsx"
+)
+
+// Symbols of the data encoding of version 2.
+var (
+	symMetaList = sx.MakeSymbol("meta-list")
+	symID       = sx.MakeSymbol("id")
+	symEncoding = sx.MakeSymbol("encoding")
+)
+
+// MigrateDataV2 converts an object of the data encoding of version 2 into the
+// data encoding of version 3. Objects that are already encoded as version 3,
+// or that are not known, are returned unchanged.
+//
+// The following objects are changed:
+//
+//   - rights, that are encoded as a number, like (rights 6), become a list of
+//     symbols, as produced by [EncodeRights].
+//   - (zettel (id N) (meta ...) (rights N) (encoding "e") (content "c"))
+//     becomes (zettel (meta ...) (rights ...) (content "e" "c")).
+//   - (content "c") becomes (content "" "c").
+//   - (meta-list (query "q") (human "h") (list (zettel (id N) (meta ...)
+//     (rights N))...)) becomes (meta-list (query "q") (human "h") (zettel
+//     "N" (meta ...) (rights ...))...).
+//   - (list (meta ...) (rights N)) becomes (list (meta ...) (rights ...)).
+func MigrateDataV2(obj sx.Object) sx.Object {
+	lst, isPair := sx.GetPair(obj)
+	if !isPair || lst == nil {
+		return obj
+	}
+	switch zsx.NodeSymbol(lst) {
+	case symRights:
+		return migrateRights(lst)
+	case symContent:
+		if args := lst.Tail(); args != nil && args.Tail() == nil {
+			return sx.MakeList(symContent, sx.MakeString(""), args.Car())
+		}
+	case SymZettel:
+		return migrateZettel(lst, false)
+	case symMetaList:
+		return migrateMetaList(lst)
+	case SymList:
+		return migrateElements(lst)
+	}
+	return lst
+}
+
+// migrateRights converts (rights N) into a list of symbols.
+func migrateRights(lst *sx.Pair) *sx.Pair {
+	n, isInt := lst.Tail().Car().(sx.Int64)
+	if !isInt {
+		return lst
+	}
+	return EncodeRights(webapi.ZettelRights(n))
+}
+
+// migrateZettel converts the elements of a zettel list. If withID is true,
+// a zettel identifier of the form (id N) is placed as a string after the
+// symbol, as in a meta-list of version 3. Otherwise it is dropped.
+func migrateZettel(lst *sx.Pair, withID bool) *sx.Pair {
+	var lb sx.ListBuilder
+	lb.Add(SymZettel)
+	var encoding sx.Object = sx.MakeString("")
+	for obj := range lst.Tail().Values() {
+		elem, isPair := sx.GetPair(obj)
+		if !isPair || elem == nil {
+			lb.Add(obj)
+			continue
+		}
+		switch zsx.NodeSymbol(elem) {
+		case symID:
+			if n, isInt := elem.Tail().Car().(sx.Int64); isInt && withID {
+				lb.Add(sx.MakeString(id.Zid(n).String()))
+			}
+		case symEncoding:
+			encoding = elem.Tail().Car()
+		case symContent:
+			if args := elem.Tail(); args != nil && args.Tail() == nil {
+				lb.Add(sx.MakeList(symContent, encoding, args.Car()))
+			} else {
+				lb.Add(elem)
+			}
+		default:
+			lb.Add(MigrateDataV2(elem))
+		}
+	}
+	return lb.List()
+}
+
+// migrateMetaList moves the zettel of a nested (list ...) into the meta-list.
+func migrateMetaList(lst *sx.Pair) *sx.Pair {
+	var lb sx.ListBuilder
+	lb.Add(symMetaList)
+	for obj := range lst.Tail().Values() {
+		elem, isPair := sx.GetPair(obj)
+		switch zsx.NodeSymbol(elem) {
+		case SymList:
+			for zobj := range elem.Tail().Values() {
+				lb.Add(migrateMetaListZettel(zobj))
+			}
+		case SymZettel:
+			lb.Add(migrateMetaListZettel(elem))
+		default:
+			if isPair {
+				lb.Add(MigrateDataV2(elem))
+			} else {
+				lb.Add(obj)
+			}
+		}
+	}
+	return lb.List()
+}
+
+func migrateMetaListZettel(obj sx.Object) sx.Object {
+	if z, isPair := sx.GetPair(obj); isPair && zsx.NodeSymbol(z) == SymZettel {
+		return migrateZettel(z, true)
+	}
+	return obj
+}
+
+// migrateElements converts all elements of the list, e.g. the metadata and
+// rights of (list (meta ...) (rights N)).
+func migrateElements(lst *sx.Pair) *sx.Pair {
+	var lb sx.ListBuilder
+	lb.Add(lst.Car())
+	for obj := range lst.Tail().Values() {
+		lb.Add(MigrateDataV2(obj))
+	}
+	return lb.List()
+}
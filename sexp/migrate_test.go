@@ -0,0 +1,85 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sexp_test
+
+import (
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/sexp"
+	"t73f.de/r/zsc/webapi"
+)
+
+func TestMigrateDataV2(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src string
+		exp string
+	}{
+		{`()`, `()`},
+		{`"abc"`, `"abc"`},
+		{`(rights 6)`, `(rights create read)`},
+		{`(rights read)`, `(rights read)`},
+		{`(content "abc")`, `(content "" "abc")`},
+		{`(content "base64" "YQ==")`, `(content "base64" "YQ==")`},
+		{`(zettel (id 1) (meta (title "T")) (rights 4) (encoding "") (content "c"))`,
+			`(zettel (meta (title "T")) (rights read) (content "" "c"))`},
+		{`(zettel (meta (title "T")) (rights read) (content "" "c"))`,
+			`(zettel (meta (title "T")) (rights read) (content "" "c"))`},
+		{`(list (meta (title "T")) (rights 30))`,
+			`(list (meta (title "T")) (rights create read update delete))`},
+		{`(meta-list (query "q") (human "h") (list (zettel (id 20260102030405) (meta) (rights 4)) (zettel (id 1) (meta (title "T")) (rights 0))))`,
+			`(meta-list (query "q") (human "h") (zettel "20260102030405" (meta) (rights read)) (zettel "00000000000001" (meta (title "T")) (rights)))`},
+		{`(meta-list (query "q") (human "h") (zettel "00000000000001" (meta) (rights read)))`,
+			`(meta-list (query "q") (human "h") (zettel "00000000000001" (meta) (rights read)))`},
+		{`(meta-list (query "q") (human "h") (list))`, `(meta-list (query "q") (human "h"))`},
+	}
+	for i, tc := range testcases {
+		obj, err := sxreader.MakeReader(strings.NewReader(tc.src)).Read()
+		if err != nil {
+			t.Errorf("%d: unable to read %q: %v", i, tc.src, err)
+			continue
+		}
+		got := sexp.MigrateDataV2(obj)
+		if s := got.String(); s != tc.exp {
+			t.Errorf("%d: expected %q, but got %q", i, tc.exp, s)
+		}
+		if again := sexp.MigrateDataV2(got).String(); again != tc.exp {
+			t.Errorf("%d: expected idempotent migration, but got %q", i, again)
+		}
+	}
+}
+
+func TestMigrateDataV2Zettel(t *testing.T) {
+	t.Parallel()
+	const src = `(zettel (id 1) (meta (title "T")) (rights 12) (encoding "base64") (content "YQ=="))`
+	obj, err := sxreader.MakeReader(strings.NewReader(src)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zd, err := sexp.ParseZettel(sexp.MigrateDataV2(obj))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := webapi.ZettelData{
+		Meta:     webapi.ZettelMeta{"title": "T"},
+		Rights:   webapi.ZettelCanRead | webapi.ZettelCanWrite,
+		Encoding: "base64",
+		Content:  "YQ==",
+	}
+	if zd.Meta["title"] != exp.Meta["title"] || zd.Rights != exp.Rights || zd.Encoding != exp.Encoding || zd.Content != exp.Content {
+		t.Errorf("expected %v, but got %v", exp, zd)
+	}
+}
@@ -51,35 +51,42 @@ func EncodeZettel(zettel webapi.ZettelData) sx.Object {
 
 // ParseZettel parses an object to contain all needed data for a zettel.
 func ParseZettel(obj sx.Object) (webapi.ZettelData, error) {
-	vals, err := ParseList(obj, "yppp")
-	if err != nil {
+	var zettel zettelSx
+	if err := Unmarshal(obj, &zettel); err != nil {
 		return webapi.ZettelData{}, err
 	}
-	if errSym := CheckSymbol(vals[0], SymZettel); errSym != nil {
-		return webapi.ZettelData{}, errSym
-	}
+	return webapi.ZettelData{
+		Meta:     zettel.Meta,
+		Rights:   webapi.ZettelRights(zettel.Rights),
+		Encoding: zettel.Content.Encoding,
+		Content:  zettel.Content.Content,
+	}, nil
+}
 
-	meta, err := ParseMeta(vals[1].(*sx.Pair))
-	if err != nil {
-		return webapi.ZettelData{}, err
-	}
+// zettelSx is the sx representation of a zettel, as used by EncodeZettel.
+type zettelSx struct {
+	_       struct{}          `sx:"zettel"`
+	Meta    webapi.ZettelMeta `sx:"meta,head"`
+	Rights  Rights            `sx:"rights"`
+	Content struct {
+		_        struct{} `sx:"content"`
+		Encoding string   `sx:"encoding"`
+		Content  string   `sx:"content"`
+	} `sx:"content"`
+}
 
-	rights, err := ParseRights(vals[2])
-	if err != nil {
-		return webapi.ZettelData{}, err
-	}
+// Rights are zettel rights that can be used as a field type for [Marshal]
+// and [Unmarshal]. They are encoded by [EncodeRights].
+type Rights webapi.ZettelRights
 
-	content, encoding, err := ParseContent(vals[3])
-	if err != nil {
-		return webapi.ZettelData{}, err
-	}
+// MarshalSx encodes the rights as a sx object.
+func (r Rights) MarshalSx() (sx.Object, error) { return EncodeRights(webapi.ZettelRights(r)), nil }
 
-	return webapi.ZettelData{
-		Meta:     meta,
-		Rights:   rights,
-		Encoding: encoding,
-		Content:  content,
-	}, nil
+// UnmarshalSx decodes the rights from a sx object.
+func (r *Rights) UnmarshalSx(obj sx.Object) error {
+	rights, err := ParseRights(obj)
+	*r = Rights(rights)
+	return err
 }
 
 // EncodeMetaRights translates metadata/rights into a sx object.
@@ -0,0 +1,139 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package shtml
+
+import (
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sxwebs/sxhtml"
+)
+
+// WriteXHTML writes a SHTML object as XHTML. In contrast to HTML, all
+// elements must be closed and only the predefined XML entities are allowed.
+func WriteXHTML(w io.Writer, obj sx.Object) error {
+	var sb strings.Builder
+	writeXHTML(&sb, obj)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeXHTML(buf *strings.Builder, obj sx.Object) {
+	switch o := obj.(type) {
+	case sx.String:
+		buf.WriteString(EscapeXML(o.GetValue()))
+	case *sx.Pair:
+		if o == nil {
+			return
+		}
+		sym, isSymbol := sx.GetSymbol(o.Car())
+		if !isSymbol {
+			for elem := range o.Values() {
+				writeXHTML(buf, elem)
+			}
+			return
+		}
+		switch {
+		case sym.IsEqualSymbol(sxhtml.SymListSplice):
+			for elem := range o.Tail().Values() {
+				writeXHTML(buf, elem)
+			}
+		case sym.IsEqualSymbol(sxhtml.SymNoEscape):
+			for elem := range o.Tail().Values() {
+				if s, isString := sx.GetString(elem); isString {
+					buf.WriteString(resolveEntities(s.GetValue()))
+				}
+			}
+		case sym.IsEqualSymbol(sxhtml.SymInlineComment), sym.IsEqualSymbol(sxhtml.SymBlockComment):
+			// Comments are not part of the publication.
+		default:
+			writeElementNode(buf, sym.GetValue(), o.Tail())
+		}
+	}
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true, "wbr": true,
+}
+
+func writeElementNode(buf *strings.Builder, name string, args *sx.Pair) {
+	buf.WriteByte('<')
+	buf.WriteString(name)
+	if attrs, isPair := sx.GetPair(args.Car()); isPair && attrs != nil {
+		if _, isAttr := sx.GetPair(attrs.Car()); isAttr {
+			writeAttributes(buf, attrs)
+			args = args.Tail()
+		}
+	}
+	if args == nil && voidElements[name] {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteByte('>')
+	for elem := range args.Values() {
+		writeXHTML(buf, elem)
+	}
+	buf.WriteString("</")
+	buf.WriteString(name)
+	buf.WriteByte('>')
+}
+
+func writeAttributes(buf *strings.Builder, attrs *sx.Pair) {
+	for obj := range attrs.Values() {
+		attr, isPair := sx.GetPair(obj)
+		if !isPair || attr == nil {
+			continue
+		}
+		key, isSymbol := sx.GetSymbol(attr.Car())
+		if !isSymbol {
+			continue
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(key.GetValue())
+		buf.WriteString(`="`)
+		if val, isString := sx.GetString(attr.Cdr()); isString {
+			buf.WriteString(EscapeXML(val.GetValue()))
+		}
+		buf.WriteByte('"')
+	}
+}
+
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+// EscapeXML escapes the characters that must not occur literally in XML text
+// or attribute values.
+func EscapeXML(s string) string { return xmlEscaper.Replace(s) }
+
+var reNamedEntity = regexp.MustCompile(`&[A-Za-z][A-Za-z0-9]*;`)
+
+// resolveEntities replaces named HTML entities, which are unknown to XML, by
+// the characters they denote.
+func resolveEntities(s string) string {
+	return reNamedEntity.ReplaceAllStringFunc(s, func(entity string) string {
+		switch entity {
+		case "&amp;", "&lt;", "&gt;", "&quot;", "&apos;":
+			return entity
+		}
+		return EscapeXML(html.UnescapeString(entity))
+	})
+}
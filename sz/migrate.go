@@ -0,0 +1,147 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz
+
+import (
+	"t73f.de/r/sx"
+	"t73f.de/r/zsx"
+)
+
+// MigrateV2 converts a sz tree that uses the encoding of version 2 into the
+// encoding of version 3. Parts of the tree that are already encoded as
+// version 3 are not changed, so it is safe to call it on any sz tree.
+//
+// The following nodes are changed:
+//
+//   - (HEADING level attrs slug fragment inline...) becomes
+//     (HEADING attrs level inline...)
+//   - (MARK mark slug fragment inline...) becomes (MARK attrs mark inline...)
+//   - list elements (BLOCK block...) become (ITEM () block...), and
+//     (INLINE inline...) become (ITEM () (PARA inline...))
+//   - a description term (INLINE inline...) becomes (TERM () inline...), and
+//     a description (BLOCK (BLOCK block...)...) becomes
+//     (DETAIL (ENTRY () block...)...)
+//
+// The fragment of a heading or of a mark is stored as attribute *ZSX-ID*,
+// as done by [AssignIdentifier]. The slug is dropped.
+//
+// The tree is changed in place. The result is the root of the converted tree.
+func MigrateV2(node *sx.Pair) *sx.Pair {
+	if node == nil {
+		return nil
+	}
+	for cell := range node.Pairs() {
+		if child, isPair := sx.GetPair(cell.Car()); isPair && child != nil {
+			cell.SetCar(MigrateV2(child))
+		}
+		if _, isPair := sx.GetPair(cell.Cdr()); !isPair {
+			break
+		}
+	}
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymHeading:
+		return migrateHeading(node)
+	case zsx.SymMark:
+		return migrateMark(node)
+	case zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote:
+		return migrateList(node)
+	case zsx.SymDescription:
+		return migrateDescription(node)
+	}
+	return node
+}
+
+// migrateHeading converts (HEADING level attrs slug fragment inline...).
+func migrateHeading(node *sx.Pair) *sx.Pair {
+	args := node.Tail()
+	level, isInt := args.Car().(sx.Int64)
+	if !isInt {
+		return node
+	}
+	attrs := args.Tail().Head()
+	fragment := args.Tail().Tail().Tail()
+	return zsx.MakeHeading(withID(attrs, fragment.Car()), int(level), fragment.Tail())
+}
+
+// migrateMark converts (MARK mark slug fragment inline...).
+func migrateMark(node *sx.Pair) *sx.Pair {
+	args := node.Tail()
+	mark, isString := sx.GetString(args.Car())
+	if !isString {
+		return node
+	}
+	fragment := args.Tail().Tail()
+	return zsx.MakeMark(withID(nil, fragment.Car()), mark.GetValue(), fragment.Tail())
+}
+
+// withID adds the given fragment as an identifier to the attributes.
+func withID(attrs *sx.Pair, fragment sx.Object) *sx.Pair {
+	s, isString := sx.GetString(fragment)
+	if !isString || s.GetValue() == "" || attrs.Assoc(zsx.SymSpecialID) != nil {
+		return attrs
+	}
+	return sx.Cons(sx.Cons(zsx.SymSpecialID, s), attrs)
+}
+
+func migrateList(node *sx.Pair) *sx.Pair {
+	attrs, elems := migrateAttrs(node)
+	var lb sx.ListBuilder
+	for obj := range elems.Values() {
+		elem, _ := sx.GetPair(obj)
+		switch zsx.NodeSymbol(elem) {
+		case zsx.SymBlock:
+			lb.Add(zsx.MakeListItem(nil, elem.Tail()))
+		case zsx.SymInline:
+			lb.Add(zsx.MakeListItem(nil, sx.MakeList(zsx.MakeParaList(elem.Tail()))))
+		default:
+			lb.Add(obj)
+		}
+	}
+	return zsx.MakeList(zsx.NodeSymbol(node), attrs, lb.List())
+}
+
+func migrateDescription(node *sx.Pair) *sx.Pair {
+	attrs, elems := migrateAttrs(node)
+	var lb sx.ListBuilder
+	for obj := range elems.Values() {
+		elem, _ := sx.GetPair(obj)
+		switch zsx.NodeSymbol(elem) {
+		case zsx.SymInline:
+			lb.Add(zsx.MakeTerm(nil, elem.Tail()))
+		case zsx.SymBlock:
+			var entries sx.ListBuilder
+			for entry := range elem.Tail().Values() {
+				if block, isPair := sx.GetPair(entry); isPair && zsx.NodeSymbol(block) == zsx.SymBlock {
+					entries.Add(zsx.MakeEntry(nil, block.Tail()))
+				} else {
+					entries.Add(entry)
+				}
+			}
+			lb.Add(zsx.MakeDetail(entries.List()))
+		default:
+			lb.Add(obj)
+		}
+	}
+	return zsx.MakeDescription(attrs, lb.List())
+}
+
+// migrateAttrs returns the attributes and the elements of a list or a
+// description. If the node has no attributes, an empty list is returned.
+func migrateAttrs(node *sx.Pair) (*sx.Pair, *sx.Pair) {
+	args := node.Tail()
+	if first, isPair := sx.GetPair(args.Car()); isPair && isNode(first) {
+		return nil, args
+	}
+	return args.Head(), args.Tail()
+}
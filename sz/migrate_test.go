@@ -0,0 +1,97 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz_test
+
+import (
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/sz"
+)
+
+func TestMigrateV2(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		name string
+		src  string
+		exp  string
+	}{
+		{name: "nil", src: "()", exp: "()"},
+
+		{name: "heading",
+			src: `(HEADING 1 () "heading" "heading" (TEXT "Heading"))`,
+			exp: `(HEADING ((*ZSX-ID* . "heading")) 1 (TEXT "Heading"))`},
+		{name: "heading without fragment",
+			src: `(HEADING 2 (("class" . "x")) "" "" (TEXT "H"))`,
+			exp: `(HEADING (("class" . "x")) 2 (TEXT "H"))`},
+		{name: "heading v3",
+			src: `(HEADING ((*ZSX-ID* . "h")) 1 (TEXT "H"))`,
+			exp: `(HEADING ((*ZSX-ID* . "h")) 1 (TEXT "H"))`},
+
+		{name: "mark",
+			src: `(PARA (MARK "m" "m" "m-1" (TEXT "x")))`,
+			exp: `(PARA (MARK ((*ZSX-ID* . "m-1")) "m" (TEXT "x")))`},
+		{name: "mark v3",
+			src: `(PARA (MARK () "m"))`,
+			exp: `(PARA (MARK () "m"))`},
+
+		{name: "list of blocks",
+			src: `(UNORDERED () (BLOCK (PARA (TEXT "a"))) (BLOCK (PARA (TEXT "b"))))`,
+			exp: `(UNORDERED () (ITEM () (PARA (TEXT "a"))) (ITEM () (PARA (TEXT "b"))))`},
+		{name: "list of inlines without attributes",
+			src: `(ORDERED (INLINE (TEXT "a")) (INLINE (TEXT "b")))`,
+			exp: `(ORDERED () (ITEM () (PARA (TEXT "a"))) (ITEM () (PARA (TEXT "b"))))`},
+		{name: "nested list",
+			src: `(QUOTATION () (BLOCK (UNORDERED () (BLOCK (HEADING 1 () "" "" (TEXT "x"))))))`,
+			exp: `(QUOTATION () (ITEM () (UNORDERED () (ITEM () (HEADING () 1 (TEXT "x"))))))`},
+		{name: "list v3",
+			src: `(ORDERED () (ITEM () (PARA (TEXT "a"))))`,
+			exp: `(ORDERED () (ITEM () (PARA (TEXT "a"))))`},
+
+		{name: "description",
+			src: `(DESCRIPTION () (INLINE (TEXT "t")) (BLOCK (BLOCK (PARA (TEXT "d1"))) (BLOCK (PARA (TEXT "d2")))))`,
+			exp: `(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (ENTRY () (PARA (TEXT "d1"))) (ENTRY () (PARA (TEXT "d2")))))`},
+		{name: "description without attributes",
+			src: `(DESCRIPTION (INLINE (TEXT "t")) (BLOCK) (INLINE (TEXT "u")))`,
+			exp: `(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL) (TERM () (TEXT "u")))`},
+		{name: "description v3",
+			src: `(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (ENTRY () (PARA (TEXT "d")))))`,
+			exp: `(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (ENTRY () (PARA (TEXT "d")))))`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			obj, err := sxreader.MakeReader(strings.NewReader(tc.src)).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			node, _ := sx.GetPair(obj)
+			got := sz.MigrateV2(node)
+			if s := got.String(); s != tc.exp {
+				t.Errorf("\nexpected: %q\n but got: %q", tc.exp, s)
+			}
+			if again := sz.MigrateV2(got).String(); again != tc.exp {
+				t.Errorf("migration is not idempotent: %q", again)
+			}
+			if got != nil {
+				if diags := sz.Validate(got); len(diags) > 0 {
+					t.Errorf("migrated tree is invalid: %v", diags)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"t73f.de/r/sx"
 	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/domain/id/idset"
 	"t73f.de/r/zsc/webapi"
 	"t73f.de/r/zsx"
 )
@@ -109,3 +110,32 @@ func SplitFragment(refValue string) (string, string) {
 	baseRef, fragment, _ := strings.Cut(refValue, "#")
 	return baseRef, fragment
 }
+
+// ZettelReferences returns the identifier of all zettel that are linked or
+// embedded within the given sz content.
+func ZettelReferences(node *sx.Pair) *idset.Set {
+	v := refCollector{zids: idset.New()}
+	zsx.WalkIt(&v, node, nil)
+	return v.zids
+}
+
+type refCollector struct {
+	zids *idset.Set
+}
+
+func (v *refCollector) VisitItBefore(node *sx.Pair, _ *sx.Pair) bool {
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymLink, zsx.SymEmbed:
+		// (LINK attrs reference inline...), (EMBED attrs reference syntax inline...)
+		refSym, refVal := zsx.GetReference(node.Tail().Tail().Head())
+		if SymRefStateZettel.IsEqualSymbol(refSym) || SymRefStateFound.IsEqualSymbol(refSym) ||
+			SymRefStateBroken.IsEqualSymbol(refSym) {
+			baseRef, _ := SplitFragment(refVal)
+			if zid, err := id.Parse(baseRef); err == nil {
+				v.zids.Add(zid)
+			}
+		}
+	}
+	return false
+}
+func (v *refCollector) VisitItAfter(*sx.Pair, *sx.Pair) {}
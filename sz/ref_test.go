@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
 	"t73f.de/r/zsc/sz"
 	"t73f.de/r/zsx"
 )
@@ -140,3 +141,23 @@ func TestSplitFragment(t *testing.T) {
 		})
 	}
 }
+
+func TestZettelReferences(t *testing.T) {
+	t.Parallel()
+	src := `(BLOCK (PARA (LINK () (ZETTEL "00010000000001") (TEXT "a"))
+  (FORMAT-EMPH () (LINK () (FOUND "00010000000002#frag")))
+  (LINK () (BROKEN "00010000000003"))
+  (LINK () (EXTERNAL "https://zettelstore.de"))
+  (LINK () (QUERY "tags:#a"))
+  (EMBED () (ZETTEL "00010000000004") "")
+  (LINK () (ZETTEL "00010000000001"))))`
+	obj, err := sxreader.MakeReader(strings.NewReader(src)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := sx.GetPair(obj)
+	exp := "{00010000000001 00010000000002 00010000000003 00010000000004}"
+	if got := sz.ZettelReferences(node).String(); got != exp {
+		t.Errorf("expected %s, but got %s", exp, got)
+	}
+}
@@ -0,0 +1,360 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz
+
+import (
+	"slices"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsx"
+)
+
+// Matcher reports whether a node of a sz tree matches. Parents contains the
+// ancestors of the node, starting with the root of the tree.
+type Matcher func(node *sx.Pair, parents []*sx.Pair) bool
+
+// Match is a node found by [Select].
+type Match struct {
+	Node    *sx.Pair   // The matching node
+	Parents []*sx.Pair // Ancestors of the node, starting with the root
+
+	cell  *sx.Pair // list cell whose car is the node
+	list  *sx.Pair // parent node, or list cell that holds a nested list
+	index int      // argument position within the parent node, or -1
+}
+
+// Select walks the sz tree, and returns all matching nodes in document order.
+// Like [zsx.Walk], it visits the nodes that are elements of a node, or that
+// are elements of a list within a node. The root node is a candidate too.
+func Select(root *sx.Pair, m Matcher) []Match {
+	if root == nil {
+		return nil
+	}
+	var result []Match
+	if m(root, nil) {
+		result = append(result, Match{Node: root, index: -1})
+	}
+	return selectChildren(result, root, []*sx.Pair{root}, m)
+}
+
+func selectChildren(result []Match, node *sx.Pair, parents []*sx.Pair, m Matcher) []Match {
+	index := 0
+	for cell := range node.Tail().Pairs() {
+		child, isPair := sx.GetPair(cell.Car())
+		if isPair && child != nil {
+			if isNode(child) {
+				result = selectNode(result, Match{Node: child, cell: cell, list: node, index: index}, parents, m)
+			} else if isNode(child.Head()) {
+				for cn := range child.Pairs() {
+					if cp, isPair2 := sx.GetPair(cn.Car()); isPair2 && isNode(cp) {
+						result = selectNode(result, Match{Node: cp, cell: cn, list: cell, index: -1}, parents, m)
+					}
+				}
+			}
+		}
+		index++
+	}
+	return result
+}
+
+func selectNode(result []Match, match Match, parents []*sx.Pair, m Matcher) []Match {
+	if m(match.Node, parents) {
+		match.Parents = slices.Clone(parents)
+		result = append(result, match)
+	}
+	return selectChildren(result, match.Node, append(parents, match.Node), m)
+}
+
+// isNode returns true, if the list is a sz node, in the sense of [zsx.Walk].
+func isNode(p *sx.Pair) bool {
+	if p == nil {
+		return false
+	}
+	sym, isSymbol := sx.GetSymbol(p.Car())
+	if !isSymbol || strings.HasPrefix(sym.GetValue(), "*") {
+		return false
+	}
+	_, isPair := sx.GetPair(p.Cdr())
+	return isPair
+}
+
+// Parent returns the parent node of the match, or nil for the root.
+func (m Match) Parent() *sx.Pair {
+	if len(m.Parents) == 0 {
+		return nil
+	}
+	return m.Parents[len(m.Parents)-1]
+}
+
+// Replace substitutes the node of the match by the given node. It returns
+// false, if the node is the root of the tree, or if it is no longer part of
+// the tree. After a successful change, the match is not valid any more.
+func (m Match) Replace(node *sx.Pair) bool {
+	if !m.attached() {
+		return false
+	}
+	m.cell.SetCar(node)
+	return true
+}
+
+// Wrap replaces the node of the match by the result of calling wrap with the
+// node, e.g. to enclose an inline node within a FORMAT-STRONG node.
+func (m Match) Wrap(wrap func(node *sx.Pair) *sx.Pair) bool {
+	if !m.attached() {
+		return false
+	}
+	m.cell.SetCar(wrap(m.Node))
+	return true
+}
+
+// Remove deletes the node of the match from its list. If the node is a
+// mandatory argument of its parent, e.g. the first text of a heading, it is
+// replaced by an empty list instead. This keeps the positions of the other
+// arguments, as expected by packages shtml and text. The reference of a link,
+// an embedded element, or a transclusion can only be replaced.
+//
+// Remove returns false, if the node is the root of the tree, if it is a
+// reference, or if it is no longer part of the tree.
+func (m Match) Remove() bool {
+	if !m.attached() || m.isReference() {
+		return false
+	}
+	switch {
+	case m.index < 0 && m.list.Car() == m.cell:
+		m.list.SetCar(m.cell.Tail())
+	case m.index < 0:
+		findPrev(m.list.Head(), m.cell).SetCdr(m.cell.Cdr())
+	case m.index < minArgs(m.list):
+		m.cell.SetCar(sx.Nil())
+	default:
+		findPrev(m.list, m.cell).SetCdr(m.cell.Cdr())
+	}
+	return true
+}
+
+// attached returns true, if the node of the match is still part of its list.
+func (m Match) attached() bool {
+	if m.cell == nil {
+		return false
+	}
+	if node, _ := sx.GetPair(m.cell.Car()); node != m.Node {
+		return false
+	}
+	if m.index < 0 {
+		return m.list.Car() == m.cell || findPrev(m.list.Head(), m.cell) != nil
+	}
+	return findPrev(m.list, m.cell) != nil
+}
+
+// isReference returns true, if the node of the match is the reference of its
+// parent node.
+func (m Match) isReference() bool {
+	switch zsx.NodeSymbol(m.list) {
+	case zsx.SymLink, zsx.SymEmbed, zsx.SymTransclude:
+		return m.index == 1
+	}
+	return false
+}
+
+func findPrev(lst, cell *sx.Pair) *sx.Pair {
+	for node := lst; node != nil; node = node.Tail() {
+		if node.Tail() == cell {
+			return node
+		}
+	}
+	return nil
+}
+
+// minArgs returns the number of arguments of a node that must be present.
+// It mirrors the evaluation of sz nodes in package shtml.
+func minArgs(node *sx.Pair) int {
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymHeading, zsx.SymBLOB, zsx.SymEmbed, zsx.SymEmbedBLOB:
+		return 3
+	case zsx.SymTable, zsx.SymRegionBlock, zsx.SymRegionQuote, zsx.SymRegionVerse,
+		zsx.SymVerbatimEval, zsx.SymVerbatimHTML, zsx.SymVerbatimMath, zsx.SymVerbatimCode,
+		zsx.SymTransclude, zsx.SymLink, zsx.SymCite, zsx.SymMark,
+		zsx.SymLiteralInput, zsx.SymLiteralMath, zsx.SymLiteralOutput, zsx.SymLiteralCode:
+		return 2
+	case zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote,
+		zsx.SymDescription, zsx.SymTerm, zsx.SymEntry, zsx.SymCell,
+		zsx.SymVerbatimComment, zsx.SymText, zsx.SymEndnote,
+		zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert, zsx.SymFormatMark,
+		zsx.SymFormatQuote, zsx.SymFormatSpan, zsx.SymFormatStrong,
+		zsx.SymFormatSub, zsx.SymFormatSuper, zsx.SymLiteralComment:
+		return 1
+	}
+	return 0
+}
+
+// hasAttributes returns true, if the first argument of the node is a list of
+// attributes.
+func hasAttributes(node *sx.Pair) bool {
+	switch zsx.NodeSymbol(node) {
+	case nil, zsx.SymBlock, zsx.SymPara, zsx.SymDetail,
+		zsx.SymInline, zsx.SymText, zsx.SymSoft, zsx.SymHard:
+		return false
+	case zsx.SymHeading, zsx.SymThematic,
+		zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote, zsx.SymListItem,
+		zsx.SymDescription, zsx.SymTerm, zsx.SymEntry, zsx.SymTable, zsx.SymRow, zsx.SymCell,
+		zsx.SymRegionBlock, zsx.SymRegionQuote, zsx.SymRegionVerse,
+		zsx.SymVerbatimComment, zsx.SymVerbatimEval, zsx.SymVerbatimHTML,
+		zsx.SymVerbatimMath, zsx.SymVerbatimCode, zsx.SymVerbatimZettel,
+		zsx.SymBLOB, zsx.SymTransclude,
+		zsx.SymLink, zsx.SymEmbed, zsx.SymEmbedBLOB, zsx.SymCite, zsx.SymMark, zsx.SymEndnote,
+		zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert, zsx.SymFormatMark,
+		zsx.SymFormatQuote, zsx.SymFormatSpan, zsx.SymFormatStrong,
+		zsx.SymFormatSub, zsx.SymFormatSuper,
+		zsx.SymLiteralComment, zsx.SymLiteralInput, zsx.SymLiteralMath,
+		zsx.SymLiteralOutput, zsx.SymLiteralCode:
+		return true
+	}
+	return false
+}
+
+// NodeAttributes returns the attributes of a sz node. The result is nil, if
+// the node has no attributes.
+func NodeAttributes(node *sx.Pair) zsx.Attributes {
+	if !hasAttributes(node) {
+		return nil
+	}
+	if attrs, isPair := sx.GetPair(node.Tail().Car()); isPair && attrs != nil {
+		return zsx.GetAttributes(attrs)
+	}
+	return nil
+}
+
+// --- Matcher
+
+// Any matches all nodes.
+func Any(*sx.Pair, []*sx.Pair) bool { return true }
+
+// Kind matches all nodes with one of the given symbols.
+func Kind(syms ...*sx.Symbol) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		if sym := zsx.NodeSymbol(node); sym != nil {
+			return slices.ContainsFunc(syms, sym.IsEqualSymbol)
+		}
+		return false
+	}
+}
+
+// And matches, if all given matchers match.
+func And(ms ...Matcher) Matcher {
+	return func(node *sx.Pair, parents []*sx.Pair) bool {
+		for _, m := range ms {
+			if !m(node, parents) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches, if at least one of the given matchers matches.
+func Or(ms ...Matcher) Matcher {
+	return func(node *sx.Pair, parents []*sx.Pair) bool {
+		for _, m := range ms {
+			if m(node, parents) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches, if the given matcher does not match.
+func Not(m Matcher) Matcher {
+	return func(node *sx.Pair, parents []*sx.Pair) bool { return !m(node, parents) }
+}
+
+// Inside matches all nodes that have an ancestor matched by m.
+func Inside(m Matcher) Matcher {
+	return func(_ *sx.Pair, parents []*sx.Pair) bool {
+		for i := len(parents) - 1; i >= 0; i-- {
+			if m(parents[i], parents[:i]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ChildOf matches all nodes whose parent is matched by m.
+func ChildOf(m Matcher) Matcher {
+	return func(_ *sx.Pair, parents []*sx.Pair) bool {
+		if l := len(parents); l > 0 {
+			return m(parents[l-1], parents[:l-1])
+		}
+		return false
+	}
+}
+
+// HasAttribute matches all nodes with an attribute of the given key.
+func HasAttribute(key string) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		_, found := NodeAttributes(node).Get(key)
+		return found
+	}
+}
+
+// AttributeIs matches all nodes with the given attribute value.
+func AttributeIs(key, value string) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		val, found := NodeAttributes(node).Get(key)
+		return found && val == value
+	}
+}
+
+// HasClass matches all nodes, where the class attribute contains the given
+// class.
+func HasClass(class string) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		val, found := NodeAttributes(node).Get("class")
+		return found && slices.Contains(strings.Fields(val), class)
+	}
+}
+
+// HasID matches all nodes with an identifier, as assigned by
+// [AssignIdentifier].
+func HasID() Matcher { return HasAttribute(zsx.SymSpecialID.GetValue()) }
+
+// HeadingLevel matches all headings of the given level.
+func HeadingLevel(level int) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		if sym := zsx.NodeSymbol(node); sym == nil || !sym.IsEqualSymbol(zsx.SymHeading) {
+			return false
+		}
+		num, isInt := node.Tail().Tail().Car().(sx.Int64)
+		return isInt && int(num) == level
+	}
+}
+
+// RefState matches all links, embedded elements, and transclusions, whose
+// reference has one of the given states, e.g. [zsx.SymRefStateExternal].
+func RefState(states ...*sx.Symbol) Matcher {
+	return func(node *sx.Pair, _ []*sx.Pair) bool {
+		switch zsx.NodeSymbol(node) {
+		case zsx.SymLink, zsx.SymEmbed, zsx.SymTransclude:
+			ref, isPair := sx.GetPair(node.Tail().Tail().Car())
+			if !isPair {
+				return false
+			}
+			sym, _ := zsx.GetReference(ref)
+			return sym != nil && slices.ContainsFunc(states, sym.IsEqualSymbol)
+		}
+		return false
+	}
+}
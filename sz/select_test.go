@@ -0,0 +1,201 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz_test
+
+import (
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/shtml"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/text"
+	"t73f.de/r/zsx"
+)
+
+const selectSrc = `(BLOCK
+ (HEADING ((*ZSX-ID* . "one")) 1 (TEXT "One"))
+ (HEADING ((*ZSX-ID* . "two")) 2 (TEXT "Two"))
+ (HEADING () 2 (TEXT "Three"))
+ (PARA (TEXT "a") (SOFT) (LINK () (EXTERNAL "https://a.example") (TEXT "ext")) (FORMAT-EMPH (("class" . "x y")) (TEXT "b")))
+ (TABLE () (ROW () (CELL () (TEXT "h")))
+  (ROW ()
+   (CELL () (LINK () (EXTERNAL "https://b.example") (TEXT "b")))
+   (CELL () (LINK () (ZETTEL "00010000000000") (TEXT "z")))))
+ (REGION-BLOCK () ((PARA (TEXT "r1")) (PARA (TEXT "r2")) (PARA (TEXT "r3"))) (TEXT "cite")))`
+
+func readSelectTree(t *testing.T) *sx.Pair {
+	t.Helper()
+	obj, err := sxreader.MakeReader(strings.NewReader(selectSrc)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, isPair := sx.GetPair(obj)
+	if !isPair {
+		t.Fatalf("not a list: %v", obj)
+	}
+	return tree
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	tree := readSelectTree(t)
+	testcases := []struct {
+		name string
+		m    sz.Matcher
+		exp  []string
+	}{
+		{"external links inside tables",
+			sz.And(sz.Kind(zsx.SymLink), sz.RefState(zsx.SymRefStateExternal), sz.Inside(sz.Kind(zsx.SymTable))),
+			[]string{`(LINK () (EXTERNAL "https://b.example") (TEXT "b"))`}},
+		{"headings of level 2 with id",
+			sz.And(sz.HeadingLevel(2), sz.HasID()),
+			[]string{`(HEADING ((*ZSX-ID* . "two")) 2 (TEXT "Two"))`}},
+		{"headings without id",
+			sz.And(sz.Kind(zsx.SymHeading), sz.Not(sz.HasID())),
+			[]string{`(HEADING () 2 (TEXT "Three"))`}},
+		{"class",
+			sz.HasClass("y"),
+			[]string{`(FORMAT-EMPH (("class" . "x y")) (TEXT "b"))`}},
+		{"text of paragraphs",
+			sz.And(sz.Kind(zsx.SymText), sz.ChildOf(sz.Kind(zsx.SymPara))),
+			[]string{`(TEXT "a")`, `(TEXT "r1")`, `(TEXT "r2")`, `(TEXT "r3")`}},
+		{"links or soft",
+			sz.Or(sz.Kind(zsx.SymSoft), sz.RefState(sz.SymRefStateZettel)),
+			[]string{`(SOFT)`, `(LINK () (ZETTEL "00010000000000") (TEXT "z"))`}},
+		{"root", sz.Kind(zsx.SymBlock), []string{strings.Join(strings.Fields(tree.String()), " ")}},
+	}
+	for _, tc := range testcases {
+		matches := sz.Select(tree, tc.m)
+		if len(matches) != len(tc.exp) {
+			t.Errorf("%s: expected %d matches, but got %d: %v", tc.name, len(tc.exp), len(matches), matches)
+			continue
+		}
+		for i, m := range matches {
+			if got := m.Node.String(); got != tc.exp[i] {
+				t.Errorf("%s/%d: expected %v, but got %v", tc.name, i, tc.exp[i], got)
+			}
+		}
+	}
+}
+
+func TestSelectParents(t *testing.T) {
+	t.Parallel()
+	tree := readSelectTree(t)
+	matches := sz.Select(tree, sz.RefState(zsx.SymRefStateExternal))
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, but got %d", len(matches))
+	}
+	exp := [][]*sx.Symbol{
+		{zsx.SymBlock, zsx.SymPara},
+		{zsx.SymBlock, zsx.SymTable, zsx.SymRow, zsx.SymCell},
+	}
+	for i, m := range matches {
+		if len(m.Parents) != len(exp[i]) {
+			t.Errorf("%d: expected %d parents, but got %v", i, len(exp[i]), m.Parents)
+			continue
+		}
+		for j, p := range m.Parents {
+			if sym := zsx.NodeSymbol(p); !sym.IsEqualSymbol(exp[i][j]) {
+				t.Errorf("%d/%d: expected parent %v, but got %v", i, j, exp[i][j], sym)
+			}
+		}
+		if m.Parent() != m.Parents[len(m.Parents)-1] {
+			t.Errorf("%d: wrong parent %v", i, m.Parent())
+		}
+	}
+	if root := sz.Select(tree, sz.Kind(zsx.SymBlock)); len(root) != 1 || root[0].Parent() != nil {
+		t.Errorf("root must have no parent, but got %v", root)
+	}
+}
+
+func TestSelectMutate(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name   string
+		m      sz.Matcher
+		mutate func(sz.Match) bool
+		exp    string
+	}{
+		{"remove soft", sz.Kind(zsx.SymSoft), sz.Match.Remove,
+			`(PARA (TEXT "a") (LINK () (EXTERNAL "https://a.example") (TEXT "ext")) (FORMAT-EMPH (("class" . "x y")) (TEXT "b")))`},
+		{"remove heading text", sz.And(sz.Kind(zsx.SymText), sz.Inside(sz.HasID())), sz.Match.Remove,
+			`(HEADING ((*ZSX-ID* . "one")) 1 ()) (HEADING ((*ZSX-ID* . "two")) 2 ())`},
+		{"remove link text", sz.And(sz.Kind(zsx.SymText), sz.ChildOf(sz.RefState(zsx.SymRefStateExternal))), sz.Match.Remove,
+			`(LINK () (EXTERNAL "https://a.example")) (FORMAT-EMPH`},
+		{"remove region paragraphs", sz.And(sz.Kind(zsx.SymPara), sz.Inside(sz.Kind(zsx.SymRegionBlock))), sz.Match.Remove,
+			`(REGION-BLOCK () () (TEXT "cite"))`},
+		{"remove by attribute", sz.AttributeIs("class", "x y"), sz.Match.Remove,
+			`(LINK () (EXTERNAL "https://a.example") (TEXT "ext")))`},
+		{"wrap external links",
+			sz.RefState(zsx.SymRefStateExternal),
+			func(m sz.Match) bool {
+				return m.Wrap(func(n *sx.Pair) *sx.Pair {
+					return zsx.MakeFormat(zsx.SymFormatStrong, nil, sx.MakeList(n))
+				})
+			},
+			`(FORMAT-STRONG () (LINK () (EXTERNAL "https://a.example") (TEXT "ext")))`},
+		{"replace emphasis", sz.Kind(zsx.SymFormatEmph),
+			func(m sz.Match) bool { return m.Replace(zsx.MakeText("plain")) },
+			`(SOFT) (LINK () (EXTERNAL "https://a.example") (TEXT "ext")) (TEXT "plain"))`},
+	}
+	for _, tc := range testcases {
+		tree := readSelectTree(t)
+		for _, m := range sz.Select(tree, tc.m) {
+			if !tc.mutate(m) {
+				t.Errorf("%s: mutation of %v failed", tc.name, m.Node)
+			}
+		}
+		if got := tree.String(); !strings.Contains(got, tc.exp) {
+			t.Errorf("%s: expected %v in\n%v", tc.name, tc.exp, got)
+		}
+		ev := shtml.NewEvaluator(1)
+		env := shtml.MakeEnvironment("en")
+		if _, err := ev.Evaluate(tree, &env); err != nil {
+			t.Errorf("%s: shtml error: %v", tc.name, err)
+		}
+		_ = text.NewEncoder().Encode(tree)
+	}
+}
+
+func TestSelectMutateStale(t *testing.T) {
+	t.Parallel()
+	tree := readSelectTree(t)
+	root := sz.Select(tree, sz.Kind(zsx.SymBlock))[0]
+	if root.Remove() || root.Replace(zsx.MakeBlock()) {
+		t.Error("root must not be changed")
+	}
+	for _, ref := range sz.Select(tree, sz.Kind(zsx.SymRefStateExternal)) {
+		if ref.Remove() {
+			t.Errorf("reference %v must not be removed", ref.Node)
+		}
+	}
+	matches := sz.Select(tree, sz.And(sz.Kind(zsx.SymPara), sz.Inside(sz.Kind(zsx.SymRegionBlock))))
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 paragraphs, but got %d", len(matches))
+	}
+	if !matches[1].Remove() {
+		t.Error("removing the second paragraph failed")
+	}
+	if matches[1].Remove() || matches[1].Replace(zsx.MakePara()) {
+		t.Error("a removed node must not be changed again")
+	}
+	if !matches[0].Remove() || !matches[2].Remove() {
+		t.Error("removing the other paragraphs failed")
+	}
+	if got := sz.Select(tree, sz.Kind(zsx.SymRegionBlock))[0].Node.String(); got != `(REGION-BLOCK () () (TEXT "cite"))` {
+		t.Errorf("unexpected region %v", got)
+	}
+}
@@ -0,0 +1,442 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsx"
+)
+
+// Diagnostic describes a part of a sz tree that does not conform to the
+// grammar of sz nodes.
+//
+// Path locates the problem, starting with the symbol of the root node. Each
+// step "SYM[i]" selects the i-th argument of a node, not counting the symbol,
+// and a further "[j]" the j-th element of a list argument. For example,
+// "BLOCK[2]/HEADING[1]" is the level of the heading that is the third
+// argument of the root block.
+type Diagnostic struct {
+	Path    string
+	Message string
+}
+
+func (d Diagnostic) String() string { return d.Path + ": " + d.Message }
+
+// ValidationError is returned by [Check], if a sz tree is not valid.
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("sz: invalid tree: ")
+	sb.WriteString(e.Diagnostics[0].String())
+	if n := len(e.Diagnostics) - 1; n > 0 {
+		sb.WriteString(" (and ")
+		sb.WriteString(strconv.Itoa(n))
+		sb.WriteString(" more)")
+	}
+	return sb.String()
+}
+
+// Check validates the sz tree and returns a [ValidationError], if the tree
+// is not valid. Renderers, like package shtml, may call it before evaluating
+// a tree that was not produced by this module.
+func Check(node *sx.Pair) error {
+	if diags := Validate(node); len(diags) > 0 {
+		return &ValidationError{Diagnostics: diags}
+	}
+	return nil
+}
+
+// Validate checks the sz tree against the grammar of sz nodes: the number
+// and types of the arguments of each node, the attributes, and the allowed
+// child nodes. The root must be a block or an inline node, or a BLOCK or
+// INLINE list. It returns all diagnostics, in document order.
+func Validate(node *sx.Pair) []Diagnostic {
+	var v validator
+	sym := zsx.NodeSymbol(node)
+	if sym == nil {
+		v.add("", "root is not a sz node: %v", node)
+		return v.diags
+	}
+	if !blockNodes[sym.GetValue()] && !inlineNodes[sym.GetValue()] {
+		v.add(sym.GetValue(), "root must be a block or an inline node")
+		return v.diags
+	}
+	v.node(sym.GetValue(), node)
+	return v.diags
+}
+
+// Kinds of fixed node arguments.
+type argKind uint8
+
+const (
+	argAttrs   argKind = iota // attribute list
+	argInt                    // integer
+	argString                 // string
+	argRef                    // reference, like (HOSTED "/")
+	argBlocks                 // list of block nodes
+	argHeader                 // ROW node, or empty list
+	argOptAttr                // attribute list, may be missing
+)
+
+// Kinds of the remaining arguments, after the fixed ones.
+type restKind uint8
+
+const (
+	restNone        restKind = iota
+	restBlocks               // block nodes
+	restInlines              // inline nodes
+	restItems                // ITEM nodes
+	restDescription          // TERM nodes, each optionally followed by a DETAIL node
+	restEntries              // ENTRY nodes
+	restRows                 // ROW nodes
+	restCells                // CELL nodes
+)
+
+// nodeSpec specifies the arguments of a node.
+type nodeSpec struct {
+	args     []argKind
+	rest     restKind
+	needRest bool // at least one remaining argument is needed
+}
+
+var (
+	specInlines = nodeSpec{rest: restInlines}
+	specFormat  = nodeSpec{args: []argKind{argAttrs}, rest: restInlines}
+	specLiteral = nodeSpec{args: []argKind{argAttrs, argString}}
+	specRegion  = nodeSpec{args: []argKind{argAttrs, argBlocks}, rest: restInlines}
+	specList    = nodeSpec{args: []argKind{argAttrs}, rest: restItems}
+)
+
+var nodeSpecs = map[string]nodeSpec{
+	zsx.SymBlock.GetValue():           {rest: restBlocks},
+	zsx.SymInline.GetValue():          specInlines,
+	zsx.SymPara.GetValue():            specInlines,
+	zsx.SymHeading.GetValue():         {args: []argKind{argAttrs, argInt}, rest: restInlines, needRest: true},
+	zsx.SymThematic.GetValue():        {args: []argKind{argOptAttr}},
+	zsx.SymListOrdered.GetValue():     specList,
+	zsx.SymListUnordered.GetValue():   specList,
+	zsx.SymListQuote.GetValue():       specList,
+	zsx.SymListItem.GetValue():        {args: []argKind{argAttrs}, rest: restBlocks},
+	zsx.SymDescription.GetValue():     {args: []argKind{argAttrs}, rest: restDescription},
+	zsx.SymTerm.GetValue():            specFormat,
+	zsx.SymDetail.GetValue():          {rest: restEntries},
+	zsx.SymEntry.GetValue():           {args: []argKind{argAttrs}, rest: restBlocks},
+	zsx.SymTable.GetValue():           {args: []argKind{argAttrs, argHeader}, rest: restRows},
+	zsx.SymRow.GetValue():             {args: []argKind{argAttrs}, rest: restCells},
+	zsx.SymCell.GetValue():            specFormat,
+	zsx.SymRegionBlock.GetValue():     specRegion,
+	zsx.SymRegionQuote.GetValue():     specRegion,
+	zsx.SymRegionVerse.GetValue():     specRegion,
+	zsx.SymVerbatimComment.GetValue(): specLiteral,
+	zsx.SymVerbatimEval.GetValue():    specLiteral,
+	zsx.SymVerbatimHTML.GetValue():    specLiteral,
+	zsx.SymVerbatimMath.GetValue():    specLiteral,
+	zsx.SymVerbatimCode.GetValue():    specLiteral,
+	zsx.SymVerbatimZettel.GetValue():  specLiteral,
+	zsx.SymBLOB.GetValue():            {args: []argKind{argAttrs, argString, argString}, rest: restInlines},
+	zsx.SymTransclude.GetValue():      {args: []argKind{argAttrs, argRef}, rest: restInlines},
+	zsx.SymText.GetValue():            {args: []argKind{argString}},
+	zsx.SymSoft.GetValue():            {},
+	zsx.SymHard.GetValue():            {},
+	zsx.SymLink.GetValue():            {args: []argKind{argAttrs, argRef}, rest: restInlines},
+	zsx.SymEmbed.GetValue():           {args: []argKind{argAttrs, argRef, argString}, rest: restInlines},
+	zsx.SymEmbedBLOB.GetValue():       {args: []argKind{argAttrs, argString, argString}, rest: restInlines},
+	zsx.SymCite.GetValue():            {args: []argKind{argAttrs, argString}, rest: restInlines},
+	zsx.SymMark.GetValue():            {args: []argKind{argAttrs, argString}, rest: restInlines},
+	zsx.SymEndnote.GetValue():         specFormat,
+	zsx.SymFormatDelete.GetValue():    specFormat,
+	zsx.SymFormatEmph.GetValue():      specFormat,
+	zsx.SymFormatInsert.GetValue():    specFormat,
+	zsx.SymFormatMark.GetValue():      specFormat,
+	zsx.SymFormatQuote.GetValue():     specFormat,
+	zsx.SymFormatSpan.GetValue():      specFormat,
+	zsx.SymFormatStrong.GetValue():    specFormat,
+	zsx.SymFormatSub.GetValue():       specFormat,
+	zsx.SymFormatSuper.GetValue():     specFormat,
+	zsx.SymLiteralComment.GetValue():  specLiteral,
+	zsx.SymLiteralInput.GetValue():    specLiteral,
+	zsx.SymLiteralMath.GetValue():     specLiteral,
+	zsx.SymLiteralOutput.GetValue():   specLiteral,
+	zsx.SymLiteralCode.GetValue():     specLiteral,
+}
+
+var blockNodes = symbolSet(
+	zsx.SymBlock, zsx.SymPara, zsx.SymHeading, zsx.SymThematic,
+	zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote,
+	zsx.SymDescription, zsx.SymTable,
+	zsx.SymRegionBlock, zsx.SymRegionQuote, zsx.SymRegionVerse,
+	zsx.SymVerbatimComment, zsx.SymVerbatimEval, zsx.SymVerbatimHTML,
+	zsx.SymVerbatimMath, zsx.SymVerbatimCode, zsx.SymVerbatimZettel,
+	zsx.SymBLOB, zsx.SymTransclude,
+)
+
+var inlineNodes = symbolSet(
+	zsx.SymInline, zsx.SymText, zsx.SymSoft, zsx.SymHard,
+	zsx.SymLink, zsx.SymEmbed, zsx.SymEmbedBLOB, zsx.SymCite, zsx.SymMark, zsx.SymEndnote,
+	zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert, zsx.SymFormatMark,
+	zsx.SymFormatQuote, zsx.SymFormatSpan, zsx.SymFormatStrong,
+	zsx.SymFormatSub, zsx.SymFormatSuper,
+	zsx.SymLiteralComment, zsx.SymLiteralInput, zsx.SymLiteralMath,
+	zsx.SymLiteralOutput, zsx.SymLiteralCode,
+)
+
+var refStates = symbolSet(
+	zsx.SymRefStateExternal, zsx.SymRefStateSelf, zsx.SymRefStateHosted, zsx.SymRefStateInvalid,
+	SymRefStateZettel, SymRefStateFound, SymRefStateBroken, SymRefStateBased, SymRefStateQuery,
+)
+
+func symbolSet(syms ...*sx.Symbol) map[string]bool {
+	result := make(map[string]bool, len(syms))
+	for _, sym := range syms {
+		result[sym.GetValue()] = true
+	}
+	return result
+}
+
+type validator struct {
+	diags []Diagnostic
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.diags = append(v.diags, Diagnostic{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// node validates a node, whose symbol is known to be in nodeSpecs.
+func (v *validator) node(path string, node *sx.Pair) {
+	sym := zsx.NodeSymbol(node)
+	spec := nodeSpecs[sym.GetValue()]
+	args, proper := listElements(node.Tail())
+	if !proper {
+		v.add(path, "not a proper list")
+		return
+	}
+	if v.outdated(path, sym, args) {
+		return
+	}
+
+	pos := 0
+	for _, kind := range spec.args {
+		if pos >= len(args) {
+			if kind != argOptAttr {
+				v.add(path, "too few arguments: expected %d, but got %d", len(spec.args), len(args))
+			}
+			return
+		}
+		v.arg(argPath(path, pos), kind, args[pos])
+		pos++
+	}
+	if spec.needRest && pos >= len(args) {
+		v.add(path, "missing text")
+	}
+	rest := args[pos:]
+	switch spec.rest {
+	case restNone:
+		if len(rest) > 0 {
+			v.add(path, "too many arguments: expected %d, but got %d", len(spec.args), len(args))
+		}
+	case restDescription:
+		v.description(path, pos, rest)
+	default:
+		for i, obj := range rest {
+			v.child(argPath(path, pos+i), spec.rest, obj)
+		}
+	}
+}
+
+// outdated detects sz encodings of version 2.
+func (v *validator) outdated(path string, sym *sx.Symbol, args []sx.Object) bool {
+	switch sym.GetValue() {
+	case zsx.SymHeading.GetValue(), zsx.SymMark.GetValue():
+		// Before version 3.0, the first argument was the level or the mark
+		// name, not the attributes.
+		if len(args) > 0 {
+			if _, isPair := sx.GetPair(args[0]); !isPair {
+				v.add(path, "outdated sz encoding: attributes must be the first argument")
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *validator) arg(path string, kind argKind, obj sx.Object) {
+	switch kind {
+	case argAttrs, argOptAttr:
+		v.attrs(path, obj)
+	case argInt:
+		if num, isInt := obj.(sx.Int64); !isInt {
+			v.add(path, "integer expected, but got %v", obj)
+		} else if num <= 0 {
+			v.add(path, "positive integer expected, but got %v", num)
+		}
+	case argString:
+		if _, isString := sx.GetString(obj); !isString {
+			v.add(path, "string expected, but got %v", obj)
+		}
+	case argRef:
+		v.reference(path, obj)
+	case argBlocks:
+		lst, isPair := sx.GetPair(obj)
+		elems, proper := listElements(lst)
+		if !isPair || !proper {
+			v.add(path, "list of blocks expected, but got %v", obj)
+			return
+		}
+		for i, elem := range elems {
+			v.child(path+"["+strconv.Itoa(i)+"]", restBlocks, elem)
+		}
+	case argHeader:
+		if sx.IsNil(obj) {
+			return
+		}
+		v.child(path, restRows, obj)
+	}
+}
+
+func (v *validator) attrs(path string, obj sx.Object) {
+	lst, isPair := sx.GetPair(obj)
+	elems, proper := listElements(lst)
+	if !isPair || !proper {
+		v.add(path, "attribute list expected, but got %v", obj)
+		return
+	}
+	for i, elem := range elems {
+		p, isPair2 := sx.GetPair(elem)
+		if !isPair2 || p == nil {
+			v.add(path+"["+strconv.Itoa(i)+"]", "attribute expected, but got %v", elem)
+			continue
+		}
+		switch p.Car().(type) {
+		case sx.String, *sx.Symbol:
+		default:
+			v.add(path+"["+strconv.Itoa(i)+"]", "attribute key must be a string or a symbol, but got %v", p.Car())
+			continue
+		}
+		if _, isString := sx.GetString(p.Cdr()); !isString {
+			v.add(path+"["+strconv.Itoa(i)+"]", "attribute value must be a string, but got %v", p.Cdr())
+		}
+	}
+}
+
+func (v *validator) reference(path string, obj sx.Object) {
+	ref, isPair := sx.GetPair(obj)
+	if !isPair || ref == nil {
+		v.add(path, "reference expected, but got %v", obj)
+		return
+	}
+	sym, _ := zsx.GetReference(ref)
+	if sym == nil || ref.Tail().Tail() != nil {
+		v.add(path, "reference expected, but got %v", obj)
+		return
+	}
+	if !refStates[sym.GetValue()] {
+		v.add(path, "unknown reference state %v", sym)
+	}
+}
+
+func (v *validator) child(path string, kind restKind, obj sx.Object) {
+	node, isPair := sx.GetPair(obj)
+	sym := zsx.NodeSymbol(node)
+	if !isPair || sym == nil {
+		v.add(path, "%s expected, but got %v", restKindName[kind], obj)
+		return
+	}
+	name := sym.GetValue()
+	if _, known := nodeSpecs[name]; !known {
+		v.add(path, "unknown node %v", sym)
+		return
+	}
+	allowed := false
+	switch kind {
+	case restBlocks:
+		allowed = blockNodes[name]
+	case restInlines:
+		allowed = inlineNodes[name]
+	case restItems:
+		allowed = sym.IsEqualSymbol(zsx.SymListItem)
+	case restEntries:
+		allowed = sym.IsEqualSymbol(zsx.SymEntry)
+	case restRows:
+		allowed = sym.IsEqualSymbol(zsx.SymRow)
+	case restCells:
+		allowed = sym.IsEqualSymbol(zsx.SymCell)
+	}
+	if !allowed {
+		msg := restKindName[kind] + " expected, but got " + name
+		if sym.IsEqualSymbol(zsx.SymBlock) && (kind == restItems || kind == restEntries) {
+			msg = "outdated sz encoding: " + msg
+		}
+		v.add(path, "%s", msg)
+		return
+	}
+	v.node(path+"/"+name, node)
+}
+
+func (v *validator) description(path string, pos int, args []sx.Object) {
+	for i := 0; i < len(args); i++ {
+		p := argPath(path, pos+i)
+		node, _ := sx.GetPair(args[i])
+		sym := zsx.NodeSymbol(node)
+		if sym == nil || !sym.IsEqualSymbol(zsx.SymTerm) {
+			msg := "TERM expected, but got %v"
+			if sym != nil && (sym.IsEqualSymbol(zsx.SymInline) || sym.IsEqualSymbol(zsx.SymBlock)) {
+				msg = "outdated sz encoding: " + msg
+			}
+			v.add(p, msg, args[i])
+			continue
+		}
+		v.node(p+"/"+sym.GetValue(), node)
+		if i+1 < len(args) {
+			next, _ := sx.GetPair(args[i+1])
+			if nextSym := zsx.NodeSymbol(next); nextSym != nil && nextSym.IsEqualSymbol(zsx.SymDetail) {
+				i++
+				v.node(argPath(path, pos+i)+"/"+nextSym.GetValue(), next)
+			}
+		}
+	}
+}
+
+var restKindName = map[restKind]string{
+	restBlocks:      "block node",
+	restInlines:     "inline node",
+	restItems:       "ITEM",
+	restDescription: "TERM",
+	restEntries:     "ENTRY",
+	restRows:        "ROW",
+	restCells:       "CELL",
+}
+
+func argPath(path string, pos int) string { return path + "[" + strconv.Itoa(pos) + "]" }
+
+// listElements returns the elements of the list, and whether it is a proper
+// list.
+func listElements(lst *sx.Pair) ([]sx.Object, bool) {
+	var result []sx.Object
+	for node := lst; node != nil; {
+		result = append(result, node.Car())
+		next, isPair := sx.GetPair(node.Cdr())
+		if !isPair {
+			return result, false
+		}
+		node = next
+	}
+	return result, true
+}
@@ -0,0 +1,156 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package sz_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/sz/zmk"
+	"t73f.de/r/zsx/input"
+)
+
+func TestValidateParsed(t *testing.T) {
+	t.Parallel()
+	testcases := []string{
+		"",
+		"abc def\nghi%%\njkl",
+		"=== Heading {-}\n==== Sub",
+		"---",
+		"* a\n* b\n** c\n# d",
+		"> quote",
+		"; term\n: detail\n: more",
+		"|=h1|=h2>\n|a|b\n|<c|:d",
+		":::{red}\nregion\n::: cite",
+		"```go\nx := 1\n```",
+		"{{{00010000000000}}}",
+		"[[a|00010000000000]] [[b|https://zettelstore.de]] {{img|00010000000000}}",
+		"[@key text] [!mark|text] Text[^note]",
+		"__e__ **s** ``code``{go} $$math$$ %% comment",
+	}
+	var parser zmk.Parser
+	for i, src := range testcases {
+		parser.Initialize(input.NewInput([]byte(src)))
+		ast := parser.Parse()
+		if diags := sz.Validate(ast); len(diags) > 0 {
+			t.Errorf("%d: %q: unexpected diagnostics %v\n%v", i, src, diags, ast)
+		}
+		if err := sz.Check(ast); err != nil {
+			t.Errorf("%d: %q: unexpected error %v", i, src, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src string
+		exp []string
+	}{
+		{`(BLOCK (PARA (TEXT "a")))`, nil},
+		{`(INLINE (TEXT "a") (SOFT))`, nil},
+		{`(TEXT "a")`, nil},
+		{`("a")`, []string{`: root is not a sz node: ("a")`}},
+		{`(ITEM ())`, []string{`ITEM: root must be a block or an inline node`}},
+		{`(BLOCK (PARA (TEXT 1)))`, []string{`BLOCK[0]/PARA[0]/TEXT[0]: string expected, but got 1`}},
+		{`(BLOCK (PARA (TEXT "a" "b")))`, []string{`BLOCK[0]/PARA[0]/TEXT: too many arguments: expected 1, but got 2`}},
+		{`(BLOCK (TEXT "a"))`, []string{`BLOCK[0]: block node expected, but got TEXT`}},
+		{`(PARA (PARA))`, []string{`PARA[0]: inline node expected, but got PARA`}},
+		{`(PARA (UNKNOWN))`, []string{`PARA[0]: unknown node UNKNOWN`}},
+		{`(PARA "a")`, []string{`PARA[0]: inline node expected, but got "a"`}},
+		{`(PARA (TEXT "a") . "b")`, []string{`PARA: not a proper list`}},
+		{`(HEADING () 1)`, []string{`HEADING: missing text`}},
+		{`(HEADING () "1" (TEXT "h"))`, []string{`HEADING[1]: integer expected, but got "1"`}},
+		{`(HEADING () 0 (TEXT "h"))`, []string{`HEADING[1]: positive integer expected, but got 0`}},
+		{`(HEADING 1 () (TEXT "h"))`, []string{`HEADING: outdated sz encoding: attributes must be the first argument`}},
+		{`(PARA (MARK "m" "s" "f" (TEXT "h")))`, []string{`PARA[0]/MARK: outdated sz encoding: attributes must be the first argument`}},
+		{`(HEADING)`, []string{`HEADING: too few arguments: expected 2, but got 0`}},
+		{`(THEMATIC)`, nil},
+		{`(THEMATIC ("a"))`, []string{`THEMATIC[0][0]: attribute expected, but got "a"`}},
+		{`(PARA (FORMAT-EMPH (("a" . 1) (1 . "b") (x . "y")) (TEXT "e")))`, []string{
+			`PARA[0]/FORMAT-EMPH[0][0]: attribute value must be a string, but got 1`,
+			`PARA[0]/FORMAT-EMPH[0][1]: attribute key must be a string or a symbol, but got 1`,
+		}},
+		{`(PARA (FORMAT-EMPH "a"))`, []string{`PARA[0]/FORMAT-EMPH[0]: attribute list expected, but got "a"`}},
+		{`(PARA (LINK () (HOSTED "/") (TEXT "l")) (LINK () "x") (LINK () (UNKNOWN "x")) (LINK () (HOSTED "a" "b")))`, []string{
+			`PARA[1]/LINK[1]: reference expected, but got "x"`,
+			`PARA[2]/LINK[1]: unknown reference state UNKNOWN`,
+			`PARA[3]/LINK[1]: reference expected, but got (HOSTED "a" "b")`,
+		}},
+		{`(UNORDERED () (ITEM () (PARA (TEXT "a"))) (BLOCK (PARA (TEXT "b"))))`, []string{
+			`UNORDERED[2]: outdated sz encoding: ITEM expected, but got BLOCK`,
+		}},
+		{`(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (ENTRY () (PARA (TEXT "d")))) (TERM ()))`, nil},
+		{`(DESCRIPTION () (INLINE (TEXT "t")) (BLOCK (PARA (TEXT "d"))))`, []string{
+			`DESCRIPTION[1]: outdated sz encoding: TERM expected, but got (INLINE (TEXT "t"))`,
+			`DESCRIPTION[2]: outdated sz encoding: TERM expected, but got (BLOCK (PARA (TEXT "d")))`,
+		}},
+		{`(DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (BLOCK (PARA (TEXT "d")))))`, []string{
+			`DESCRIPTION[2]/DETAIL[0]: outdated sz encoding: ENTRY expected, but got BLOCK`,
+		}},
+		{`(TABLE () () (ROW () (CELL () (TEXT "a"))))`, nil},
+		{`(TABLE () (ROW () (CELL () (TEXT "h"))) (ROW () (TEXT "a")))`, []string{
+			`TABLE[2]/ROW[1]: CELL expected, but got TEXT`,
+		}},
+		{`(TABLE () (CELL ()))`, []string{`TABLE[1]: ROW expected, but got CELL`}},
+		{`(REGION-BLOCK () ((PARA (TEXT "a")) (TEXT "b")) (TEXT "c"))`, []string{
+			`REGION-BLOCK[1][1]: block node expected, but got TEXT`,
+		}},
+		{`(REGION-QUOTE () "a")`, []string{`REGION-QUOTE[1]: list of blocks expected, but got "a"`}},
+		{`(VERBATIM-CODE (("" . "go")) "x")`, nil},
+		{`(VERBATIM-CODE ())`, []string{`VERBATIM-CODE: too few arguments: expected 2, but got 1`}},
+		{`(BLOB () "svg" "<svg/>" (TEXT "t"))`, nil},
+		{`(PARA (EMBED () (ZETTEL "00010000000000") 1))`, []string{`PARA[0]/EMBED[2]: string expected, but got 1`}},
+	}
+	for i, tc := range testcases {
+		obj, err := sxreader.MakeReader(strings.NewReader(tc.src)).Read()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		node, _ := sx.GetPair(obj)
+		diags := sz.Validate(node)
+		if len(diags) != len(tc.exp) {
+			t.Errorf("%d: %s\nexpected %v, but got %v", i, tc.src, tc.exp, diags)
+			continue
+		}
+		for j, d := range diags {
+			if got := d.String(); got != tc.exp[j] {
+				t.Errorf("%d/%d: %s\nexpected %q, but got %q", i, j, tc.src, tc.exp[j], got)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	obj, err := sxreader.MakeReader(strings.NewReader(`(BLOCK (PARA (TEXT 1) (TEXT 2)))`)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sz.Check(obj.(*sx.Pair))
+	var verr *sz.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("ValidationError expected, but got %v", err)
+	}
+	if len(verr.Diagnostics) != 2 {
+		t.Errorf("expected 2 diagnostics, but got %v", verr.Diagnostics)
+	}
+	if exp := "sz: invalid tree: BLOCK[0]/PARA[0]/TEXT[0]: string expected, but got 1 (and 1 more)"; err.Error() != exp {
+		t.Errorf("expected %q, but got %q", exp, err.Error())
+	}
+}
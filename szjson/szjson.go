@@ -0,0 +1,361 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+// Package szjson maps sz and zettel data to JSON and back, for clients that
+// cannot process s-expressions.
+//
+// The mapping of sz objects is lossless. A string is mapped to a JSON string,
+// an integer to a JSON number, and a symbol to an object {"sym": name}. A
+// proper list whose first element is a symbol is a node. It is mapped to an
+// object {"t": symbol, "a": attributes, "c": [elements...]}, where "c"
+// contains the remaining elements of the list, and is omitted if there are
+// none. Any other proper list, including the empty list, is mapped to a JSON
+// array. A pair that is not part of a proper list is mapped to an object
+// {"car": car, "cdr": cdr}.
+//
+// Nodes that start with attributes, e.g. a heading or a link, store them in
+// "a" as a list of [key, value] pairs, in the order of the sz attribute list.
+// A key is a string, or a symbol object for special keys like "*ZSX-ID*". An
+// empty attribute list is written as "a": []. If "a" is missing, the node
+// has no attributes.
+//
+// For example, the sz paragraph
+//
+//	(PARA (LINK (("class" . "x")) (HOSTED "/a") (TEXT "A")) (SOFT))
+//
+// is mapped to
+//
+//	{"t":"PARA","c":[
+//	  {"t":"LINK","a":[["class","x"]],"c":[{"t":"HOSTED","c":["/a"]},{"t":"TEXT","c":["A"]}]},
+//	  {"t":"SOFT"}]}
+package szjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsx"
+)
+
+// Node is the JSON representation of a sz node.
+type Node struct {
+	T string `json:"t"`
+	A []any  `json:"a,omitzero"`
+	C []any  `json:"c,omitempty"`
+}
+
+// Symbol is the JSON representation of a symbol that is not the first
+// element of a node.
+type Symbol struct {
+	Sym string `json:"sym"`
+}
+
+// Cons is the JSON representation of a pair that is not part of a proper
+// list.
+type Cons struct {
+	Car any `json:"car"`
+	Cdr any `json:"cdr"`
+}
+
+// MarshalSz returns the JSON encoding of the given sz object.
+func MarshalSz(obj sx.Object) ([]byte, error) {
+	v, err := EncodeSz(obj)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalSz parses the JSON encoding of a sz object.
+func UnmarshalSz(data []byte) (sx.Object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return DecodeSz(v)
+}
+
+// EncodeSz transforms the given sz object into a value that can be encoded
+// by [encoding/json].
+func EncodeSz(obj sx.Object) (any, error) {
+	switch o := obj.(type) {
+	case sx.String:
+		return o.GetValue(), nil
+	case sx.Int64:
+		return int64(o), nil
+	case *sx.Symbol:
+		return Symbol{Sym: o.GetValue()}, nil
+	case *sx.Pair:
+		return encodePair(o)
+	}
+	if sx.IsNil(obj) {
+		return []any{}, nil
+	}
+	return nil, fmt.Errorf("szjson: unsupported object %v/%T", obj, obj)
+}
+
+func encodePair(pair *sx.Pair) (any, error) {
+	if pair == nil {
+		return []any{}, nil
+	}
+	if !isProperList(pair) {
+		car, err := EncodeSz(pair.Car())
+		if err != nil {
+			return nil, err
+		}
+		cdr, err := EncodeSz(pair.Cdr())
+		if err != nil {
+			return nil, err
+		}
+		return Cons{Car: car, Cdr: cdr}, nil
+	}
+	sym, isSymbol := sx.GetSymbol(pair.Car())
+	if !isSymbol {
+		return encodeList(pair)
+	}
+	node := Node{T: sym.GetValue()}
+	rest := pair.Tail()
+	if attrNodes[sym.GetValue()] && rest != nil {
+		if attrs, isAttrs := encodeAttrs(rest.Car()); isAttrs {
+			node.A = attrs
+			rest = rest.Tail()
+		}
+	}
+	if rest != nil {
+		c, err := encodeList(rest)
+		if err != nil {
+			return nil, err
+		}
+		node.C = c
+	}
+	return node, nil
+}
+
+func isProperList(pair *sx.Pair) bool {
+	for node := pair; node != nil; {
+		next, isPair := sx.GetPair(node.Cdr())
+		if !isPair {
+			return false
+		}
+		node = next
+	}
+	return true
+}
+
+func encodeList(lst *sx.Pair) ([]any, error) {
+	result := []any{}
+	for obj := range lst.Values() {
+		v, err := EncodeSz(obj)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// encodeAttrs returns the JSON representation of an attribute list, if obj
+// is an association list with string values.
+func encodeAttrs(obj sx.Object) ([]any, bool) {
+	lst, isPair := sx.GetPair(obj)
+	if !isPair || !isProperList(lst) {
+		return nil, false
+	}
+	result := []any{}
+	for elem := range lst.Values() {
+		p, isPair2 := sx.GetPair(elem)
+		if !isPair2 || p == nil {
+			return nil, false
+		}
+		val, isString := sx.GetString(p.Cdr())
+		if !isString {
+			return nil, false
+		}
+		switch key := p.Car().(type) {
+		case sx.String:
+			result = append(result, []any{key.GetValue(), val.GetValue()})
+		case *sx.Symbol:
+			result = append(result, []any{Symbol{Sym: key.GetValue()}, val.GetValue()})
+		default:
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+// attrNodes contains the names of all nodes, where the first element after
+// the symbol is an attribute list.
+var attrNodes = map[string]bool{}
+
+func init() {
+	for _, sym := range []*sx.Symbol{
+		zsx.SymHeading, zsx.SymThematic,
+		zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote, zsx.SymListItem,
+		zsx.SymDescription, zsx.SymTerm, zsx.SymEntry,
+		zsx.SymTable, zsx.SymRow, zsx.SymCell,
+		zsx.SymRegionBlock, zsx.SymRegionQuote, zsx.SymRegionVerse,
+		zsx.SymVerbatimComment, zsx.SymVerbatimEval, zsx.SymVerbatimHTML,
+		zsx.SymVerbatimMath, zsx.SymVerbatimCode, zsx.SymVerbatimZettel,
+		zsx.SymBLOB, zsx.SymTransclude,
+		zsx.SymLink, zsx.SymEmbed, zsx.SymEmbedBLOB, zsx.SymCite, zsx.SymMark, zsx.SymEndnote,
+		zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert, zsx.SymFormatMark,
+		zsx.SymFormatQuote, zsx.SymFormatSpan, zsx.SymFormatStrong,
+		zsx.SymFormatSub, zsx.SymFormatSuper,
+		zsx.SymLiteralComment, zsx.SymLiteralInput, zsx.SymLiteralMath,
+		zsx.SymLiteralOutput, zsx.SymLiteralCode,
+	} {
+		attrNodes[sym.GetValue()] = true
+	}
+}
+
+// DecodeSz transforms a JSON value, as produced by [EncodeSz] or decoded by
+// [encoding/json], into a sz object.
+func DecodeSz(v any) (sx.Object, error) {
+	switch val := v.(type) {
+	case nil:
+		return sx.Nil(), nil
+	case string:
+		return sx.MakeString(val), nil
+	case int64:
+		return sx.Int64(val), nil
+	case json.Number:
+		i, err := val.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("szjson: not an integer: %v", val)
+		}
+		return sx.Int64(i), nil
+	case float64:
+		if val != math.Trunc(val) || math.Abs(val) > 1<<53 {
+			return nil, fmt.Errorf("szjson: not an integer: %v", val)
+		}
+		return sx.Int64(int64(val)), nil
+	case []any:
+		return decodeList(nil, val)
+	case Symbol:
+		return sx.MakeSymbol(val.Sym), nil
+	case Cons:
+		return decodeCons(val.Car, val.Cdr)
+	case Node:
+		return decodeNode(val.T, val.A, val.A != nil, val.C)
+	case map[string]any:
+		return decodeObject(val)
+	}
+	return nil, fmt.Errorf("szjson: unsupported value %v/%T", v, v)
+}
+
+func decodeObject(obj map[string]any) (sx.Object, error) {
+	if t, found := obj["t"]; found {
+		sym, isString := t.(string)
+		if !isString {
+			return nil, fmt.Errorf("szjson: node type is not a string: %v/%T", t, t)
+		}
+		var attrs, content []any
+		a, hasAttrs := obj["a"]
+		if hasAttrs {
+			lst, isList := a.([]any)
+			if !isList {
+				return nil, fmt.Errorf("szjson: attributes of %s are not a list: %v/%T", sym, a, a)
+			}
+			attrs = lst
+		}
+		if c, found2 := obj["c"]; found2 {
+			lst, isList := c.([]any)
+			if !isList {
+				return nil, fmt.Errorf("szjson: content of %s is not a list: %v/%T", sym, c, c)
+			}
+			content = lst
+		}
+		return decodeNode(sym, attrs, hasAttrs, content)
+	}
+	if sym, found := obj["sym"]; found {
+		if s, isString := sym.(string); isString {
+			return sx.MakeSymbol(s), nil
+		}
+		return nil, fmt.Errorf("szjson: symbol is not a string: %v/%T", sym, sym)
+	}
+	car, hasCar := obj["car"]
+	cdr, hasCdr := obj["cdr"]
+	if hasCar && hasCdr {
+		return decodeCons(car, cdr)
+	}
+	return nil, fmt.Errorf("szjson: unknown object: %v", obj)
+}
+
+func decodeNode(sym string, attrs []any, hasAttrs bool, content []any) (sx.Object, error) {
+	var lb sx.ListBuilder
+	lb.Add(sx.MakeSymbol(sym))
+	if hasAttrs {
+		alst, err := decodeAttrs(attrs)
+		if err != nil {
+			return nil, fmt.Errorf("szjson: attributes of %s: %w", sym, err)
+		}
+		lb.Add(alst)
+	}
+	return decodeList(&lb, content)
+}
+
+func decodeAttrs(attrs []any) (*sx.Pair, error) {
+	var lb sx.ListBuilder
+	for _, attr := range attrs {
+		kv, isList := attr.([]any)
+		if !isList || len(kv) != 2 {
+			return nil, fmt.Errorf("not a key/value pair: %v", attr)
+		}
+		key, err := DecodeSz(kv[0])
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case sx.String, *sx.Symbol:
+		default:
+			return nil, fmt.Errorf("key must be a string or a symbol: %v", kv[0])
+		}
+		val, isString := kv[1].(string)
+		if !isString {
+			return nil, fmt.Errorf("value of %v is not a string: %v/%T", key, kv[1], kv[1])
+		}
+		lb.Add(sx.Cons(key, sx.MakeString(val)))
+	}
+	return lb.List(), nil
+}
+
+func decodeList(lb *sx.ListBuilder, vals []any) (sx.Object, error) {
+	if lb == nil {
+		lb = &sx.ListBuilder{}
+	}
+	for _, v := range vals {
+		obj, err := DecodeSz(v)
+		if err != nil {
+			return nil, err
+		}
+		lb.Add(obj)
+	}
+	return lb.List(), nil
+}
+
+func decodeCons(car, cdr any) (sx.Object, error) {
+	carObj, err := DecodeSz(car)
+	if err != nil {
+		return nil, err
+	}
+	cdrObj, err := DecodeSz(cdr)
+	if err != nil {
+		return nil, err
+	}
+	return sx.Cons(carObj, cdrObj), nil
+}
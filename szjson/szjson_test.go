@@ -0,0 +1,208 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package szjson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/sx/sxreader"
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/sexp"
+	"t73f.de/r/zsc/sz"
+	"t73f.de/r/zsc/szjson"
+	"t73f.de/r/zsc/webapi"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// TestGolden encodes all sx files in testdata as JSON, compares the result
+// with the golden JSON file, and decodes it back to sx.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+	files, err := filepath.Glob(filepath.Join("testdata", "*.sx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no golden files found")
+	}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, err := sxreader.MakeReader(bytes.NewReader(src)).Read()
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+
+		var data []byte
+		var decode func([]byte) (sx.Object, error)
+		if strings.HasPrefix(filepath.Base(file), "zettel-") {
+			zd, errParse := sexp.ParseZettel(obj)
+			if errParse != nil {
+				t.Errorf("%s: %v", file, errParse)
+				continue
+			}
+			data, err = szjson.MarshalZettel(zd)
+			decode = func(data []byte) (sx.Object, error) {
+				zd, errUnmarshal := szjson.UnmarshalZettel(data)
+				return sexp.EncodeZettel(zd), errUnmarshal
+			}
+		} else {
+			node, _ := sx.GetPair(obj)
+			if errCheck := sz.Check(node); errCheck != nil {
+				t.Errorf("%s: %v", file, errCheck)
+				continue
+			}
+			data, err = szjson.MarshalSz(obj)
+			decode = szjson.UnmarshalSz
+		}
+		if err != nil {
+			t.Errorf("%s: encode error: %v", file, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, data, "", "  "); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteByte('\n')
+
+		goldenFile := strings.TrimSuffix(file, ".sx") + ".json"
+		if *update {
+			if err = os.WriteFile(goldenFile, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		golden, err := os.ReadFile(goldenFile)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), golden) {
+			t.Errorf("%s: JSON differs from %s:\n%s", file, goldenFile, buf.String())
+		}
+
+		got, err := decode(golden)
+		if err != nil {
+			t.Errorf("%s: decode error: %v", goldenFile, err)
+			continue
+		}
+		if exp := obj.String(); got.String() != exp {
+			t.Errorf("%s:\nexpected: %v\n but got: %v", goldenFile, exp, got)
+		}
+	}
+}
+
+func TestDecodeSzErrors(t *testing.T) {
+	t.Parallel()
+	testcases := []string{
+		`true`,
+		`1.5`,
+		`{"x":1}`,
+		`{"t":1}`,
+		`{"t":"PARA","c":"x"}`,
+		`{"t":"LINK","a":[["a"]]}`,
+		`{"t":"LINK","a":[[1,"b"]]}`,
+		`{"t":"LINK","a":[["a",1]]}`,
+		`{"sym":2}`,
+	}
+	for i, tc := range testcases {
+		if obj, err := szjson.UnmarshalSz([]byte(tc)); err == nil {
+			t.Errorf("%d: %s should fail, but got %v", i, tc, obj)
+		}
+	}
+}
+
+func TestEncodeDecodeSz(t *testing.T) {
+	t.Parallel()
+	symText := sx.MakeSymbol("TEXT")
+	testcases := []sx.Object{
+		sx.MakeList(sx.MakeSymbol("PARA"), sx.MakeList(symText, sx.MakeString("a"))),
+		sx.Cons(symText, sx.Cons(sx.MakeString("x"), sx.MakeString("y"))), // improper list
+	}
+	for i, obj := range testcases {
+		v, err := szjson.EncodeSz(obj)
+		if err != nil {
+			t.Errorf("%d: encode error: %v", i, err)
+			continue
+		}
+		got, err := szjson.DecodeSz(v)
+		if err != nil {
+			t.Errorf("%d: decode error: %v", i, err)
+			continue
+		}
+		if got.String() != obj.String() {
+			t.Errorf("%d: expected %v, but got %v", i, obj, got)
+		}
+	}
+}
+
+func TestMetaRights(t *testing.T) {
+	t.Parallel()
+	mr := webapi.MetaRights{
+		Meta:   webapi.ZettelMeta{"title": "T", "tags": "#x"},
+		Rights: webapi.ZettelCanRead | webapi.ZettelCanWrite,
+	}
+	data, err := szjson.MarshalMetaRights(mr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"meta":{"tags":"#x","title":"T"},"rights":["read","update"]}`; string(data) != exp {
+		t.Errorf("expected %s, but got %s", exp, data)
+	}
+	got, err := szjson.UnmarshalMetaRights(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rights != mr.Rights || len(got.Meta) != len(mr.Meta) || got.Meta["title"] != "T" {
+		t.Errorf("expected %v, but got %v", mr, got)
+	}
+	if _, err = szjson.UnmarshalMetaRights([]byte(`{"rights":["fly"]}`)); err == nil {
+		t.Error("unknown right should fail")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+	agg := webapi.Aggregate{
+		"a": {id.ZidDefaultHome, id.MustParse("20260101120000")},
+		"b": {},
+	}
+	data, err := szjson.MarshalAggregate(agg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"a":["00010000000000","20260101120000"],"b":[]}`; string(data) != exp {
+		t.Errorf("expected %s, but got %s", exp, data)
+	}
+	got, err := szjson.UnmarshalAggregate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got["a"]) != 2 || got["a"][1] != agg["a"][1] || len(got["b"]) != 0 {
+		t.Errorf("expected %v, but got %v", agg, got)
+	}
+	if _, err = szjson.UnmarshalAggregate([]byte(`{"a":["abc"]}`)); err == nil {
+		t.Error("invalid zettel identifier should fail")
+	}
+}
@@ -0,0 +1,172 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package szjson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"t73f.de/r/zsc/domain/id"
+	"t73f.de/r/zsc/webapi"
+)
+
+// zettelJSON is the JSON representation of zettel data, e.g.
+//
+//	{"meta":{"title":"A"},"rights":["read"],"encoding":"","content":"abc"}
+type zettelJSON struct {
+	Meta     webapi.ZettelMeta `json:"meta"`
+	Rights   Rights            `json:"rights"`
+	Encoding string            `json:"encoding"`
+	Content  string            `json:"content"`
+}
+
+// metaRightsJSON is the JSON representation of metadata and rights.
+type metaRightsJSON struct {
+	Meta   webapi.ZettelMeta `json:"meta"`
+	Rights Rights            `json:"rights"`
+}
+
+// MarshalZettel returns the JSON encoding of the given zettel data.
+func MarshalZettel(zd webapi.ZettelData) ([]byte, error) {
+	return json.Marshal(zettelJSON{
+		Meta:     nonNilMeta(zd.Meta),
+		Rights:   Rights(zd.Rights),
+		Encoding: zd.Encoding,
+		Content:  zd.Content,
+	})
+}
+
+// UnmarshalZettel parses the JSON encoding of zettel data.
+func UnmarshalZettel(data []byte) (webapi.ZettelData, error) {
+	var zj zettelJSON
+	if err := json.Unmarshal(data, &zj); err != nil {
+		return webapi.ZettelData{}, err
+	}
+	return webapi.ZettelData{
+		Meta:     zj.Meta,
+		Rights:   webapi.ZettelRights(zj.Rights),
+		Encoding: zj.Encoding,
+		Content:  zj.Content,
+	}, nil
+}
+
+// MarshalMetaRights returns the JSON encoding of the given metadata and
+// rights.
+func MarshalMetaRights(mr webapi.MetaRights) ([]byte, error) {
+	return json.Marshal(metaRightsJSON{Meta: nonNilMeta(mr.Meta), Rights: Rights(mr.Rights)})
+}
+
+// UnmarshalMetaRights parses the JSON encoding of metadata and rights.
+func UnmarshalMetaRights(data []byte) (webapi.MetaRights, error) {
+	var mrj metaRightsJSON
+	if err := json.Unmarshal(data, &mrj); err != nil {
+		return webapi.MetaRights{}, err
+	}
+	return webapi.MetaRights{Meta: mrj.Meta, Rights: webapi.ZettelRights(mrj.Rights)}, nil
+}
+
+func nonNilMeta(m webapi.ZettelMeta) webapi.ZettelMeta {
+	if m == nil {
+		return webapi.ZettelMeta{}
+	}
+	return m
+}
+
+// MarshalAggregate returns the JSON encoding of an aggregate. It is an object
+// that maps each key to a list of zettel identifier, given as strings, e.g.
+// {"tag":["00001000000000","20260101120000"]}.
+func MarshalAggregate(agg webapi.Aggregate) ([]byte, error) {
+	result := make(map[string][]string, len(agg))
+	for key, zids := range agg {
+		lst := make([]string, 0, len(zids))
+		for _, zid := range zids {
+			lst = append(lst, zid.String())
+		}
+		result[key] = lst
+	}
+	return json.Marshal(result)
+}
+
+// UnmarshalAggregate parses the JSON encoding of an aggregate.
+func UnmarshalAggregate(data []byte) (webapi.Aggregate, error) {
+	var aj map[string][]string
+	if err := json.Unmarshal(data, &aj); err != nil {
+		return nil, err
+	}
+	if aj == nil {
+		return nil, nil
+	}
+	agg := make(webapi.Aggregate, len(aj))
+	for key, lst := range aj {
+		zids := make([]id.Zid, 0, len(lst))
+		for _, s := range lst {
+			zid, err := id.Parse(s)
+			if err != nil {
+				return nil, fmt.Errorf("szjson: aggregate %q: %w", key, err)
+			}
+			zids = append(zids, zid)
+		}
+		agg[key] = zids
+	}
+	return agg, nil
+}
+
+// Rights are zettel rights, encoded as a JSON list of the names "create",
+// "read", "update", and "delete", like [t73f.de/r/zsc/sexp.EncodeRights].
+type Rights webapi.ZettelRights
+
+var rightNames = []struct {
+	right webapi.ZettelRights
+	name  string
+}{
+	{webapi.ZettelCanCreate, "create"},
+	{webapi.ZettelCanRead, "read"},
+	{webapi.ZettelCanWrite, "update"},
+	{webapi.ZettelCanDelete, "delete"},
+}
+
+// MarshalJSON encodes the rights as a list of names.
+func (r Rights) MarshalJSON() ([]byte, error) {
+	names := []string{}
+	for _, rn := range rightNames {
+		if webapi.ZettelRights(r)&rn.right != 0 {
+			names = append(names, rn.name)
+		}
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes the rights from a list of names.
+func (r *Rights) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	result := webapi.ZettelRights(0)
+	for _, name := range names {
+		found := false
+		for _, rn := range rightNames {
+			if rn.name == name {
+				result |= rn.right
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("szjson: unknown right %q", name)
+		}
+	}
+	*r = Rights(result)
+	return nil
+}
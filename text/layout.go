@@ -0,0 +1,479 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2026-present Detlef Stern
+//
+// This file is part of zettelstore-client.
+//
+// Zettelstore client is licensed under the latest version of the EUPL
+// (European Union Public License). Please see file LICENSE.txt for your rights
+// and obligations under this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2026-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package text
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"t73f.de/r/sx"
+	"t73f.de/r/zsx"
+)
+
+// LayoutEncoder encodes a sz tree as plain text, while retaining its block
+// structure: paragraphs are wrapped, lists are indented, tables are drawn
+// with ASCII characters, headings are underlined, and endnotes are appended.
+type LayoutEncoder struct {
+	width    int
+	endnotes []*sx.Pair
+}
+
+// NewLayoutEncoder returns a new layout encoder that wraps lines at the given
+// width. A width less or equal zero disables line wrapping.
+func NewLayoutEncoder(width int) *LayoutEncoder {
+	return &LayoutEncoder{width: width}
+}
+
+// Encode the given sz node, typically a BLOCK or INLINE node, as plain text.
+func (le *LayoutEncoder) Encode(node *sx.Pair) string {
+	le.endnotes = nil
+	var lines []string
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymBlock:
+		lines = le.layoutBlocks(node.Tail(), le.width, false)
+	case zsx.SymInline:
+		lines = wrapText(le.inlineString(node.Tail()), le.width)
+	default:
+		lines = le.layoutBlock(node, le.width)
+	}
+	lines = le.appendEndnotes(lines)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (le *LayoutEncoder) appendEndnotes(lines []string) []string {
+	// Rendering an endnote may add more endnotes, so le.endnotes may grow.
+	for i := 0; i < len(le.endnotes); i++ {
+		if i == 0 && len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		marker := "[" + strconv.Itoa(i+1) + "] "
+		text := wrapText(le.inlineString(le.endnotes[i]), reduceWidth(le.width, len(marker)))
+		lines = append(lines, prefixLines(text, marker, strings.Repeat(" ", len(marker)))...)
+	}
+	return lines
+}
+
+// layoutBlocks separates the given blocks by an empty line. Within a list item,
+// a nested list directly follows the preceding block.
+func (le *LayoutEncoder) layoutBlocks(lst *sx.Pair, width int, inItem bool) []string {
+	var result []string
+	for obj := range lst.Values() {
+		node, isPair := sx.GetPair(obj)
+		if !isPair || node == nil {
+			continue
+		}
+		lines := le.layoutBlock(node, width)
+		if len(lines) == 0 {
+			continue
+		}
+		if len(result) > 0 && !(inItem && isListNode(node)) {
+			result = append(result, "")
+		}
+		result = append(result, lines...)
+	}
+	return result
+}
+
+func isListNode(node *sx.Pair) bool {
+	switch zsx.NodeSymbol(node) {
+	case zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote:
+		return true
+	}
+	return false
+}
+
+func (le *LayoutEncoder) layoutBlock(node *sx.Pair, width int) []string {
+	switch sym := zsx.NodeSymbol(node); sym {
+	case zsx.SymBlock:
+		return le.layoutBlocks(node.Tail(), width, false)
+	case zsx.SymPara:
+		return wrapText(le.inlineString(node.Tail()), width)
+	case zsx.SymHeading:
+		return le.layoutHeading(node, width)
+	case zsx.SymThematic:
+		return []string{strings.Repeat("-", thematicWidth(width))}
+	case zsx.SymListOrdered, zsx.SymListUnordered, zsx.SymListQuote:
+		return le.layoutList(sym, node, width)
+	case zsx.SymDescription:
+		return le.layoutDescription(node, width)
+	case zsx.SymTable:
+		return le.layoutTable(node)
+	case zsx.SymRegionBlock, zsx.SymRegionVerse:
+		return le.layoutRegion(node, width, "")
+	case zsx.SymRegionQuote:
+		return le.layoutRegion(node, width, "> ")
+	case zsx.SymVerbatimCode, zsx.SymVerbatimEval, zsx.SymVerbatimMath,
+		zsx.SymVerbatimHTML, zsx.SymVerbatimZettel:
+		return layoutVerbatim(node)
+	case zsx.SymBLOB:
+		// (BLOB attrs syntax data inline...)
+		return wrapText(le.inlineString(node.Tail().Tail().Tail().Tail()), width)
+	}
+	return nil
+}
+
+const headingUnderline = "=-~^\""
+
+func (le *LayoutEncoder) layoutHeading(node *sx.Pair, width int) []string {
+	// (HEADING attrs level inline...)
+	levelNode := node.Tail().Tail()
+	level := 1
+	if num, isInt := levelNode.Car().(sx.Int64); isInt && num > 0 {
+		level = int(num)
+	}
+	lines := wrapText(le.inlineString(levelNode.Tail()), width)
+	if len(lines) == 0 {
+		return nil
+	}
+	maxLen := 0
+	for _, line := range lines {
+		maxLen = max(maxLen, utf8.RuneCountInString(line))
+	}
+	ch := headingUnderline[min(level, len(headingUnderline))-1]
+	return append(lines, strings.Repeat(string(ch), maxLen))
+}
+
+func thematicWidth(width int) int {
+	if width <= 0 {
+		return 72
+	}
+	return width
+}
+
+func (le *LayoutEncoder) layoutList(sym *sx.Symbol, node *sx.Pair, width int) []string {
+	// (ORDERED attrs (ITEM attrs block...)...)
+	var result []string
+	num := 0
+	for obj := range node.Tail().Tail().Values() {
+		item, isPair := sx.GetPair(obj)
+		if !isPair || item == nil {
+			continue
+		}
+		num++
+		var marker string
+		switch sym {
+		case zsx.SymListOrdered:
+			marker = strconv.Itoa(num) + ". "
+		case zsx.SymListQuote:
+			marker = "> "
+		default:
+			marker = "* "
+		}
+		_, elems := zsx.GetListItem(item)
+		lines := le.layoutBlocks(elems, reduceWidth(width, len(marker)), true)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		rest := strings.Repeat(" ", len(marker))
+		if sym == zsx.SymListQuote {
+			rest = marker
+		}
+		result = append(result, prefixLines(lines, marker, rest)...)
+	}
+	return result
+}
+
+func (le *LayoutEncoder) layoutDescription(node *sx.Pair, width int) []string {
+	// (DESCRIPTION attrs (TERM attrs inline...) (DETAIL (ENTRY attrs block...)...)...)
+	const indent = "    "
+	_, rest := zsx.GetDescription(node)
+	var result []string
+	for obj := range rest.Values() {
+		elem, isPair := sx.GetPair(obj)
+		if !isPair || elem == nil {
+			continue
+		}
+		switch zsx.NodeSymbol(elem) {
+		case zsx.SymTerm:
+			if len(result) > 0 {
+				result = append(result, "")
+			}
+			result = append(result, wrapText(le.inlineString(elem.Tail().Tail()), width)...)
+		case zsx.SymDetail:
+			for entryObj := range elem.Tail().Values() {
+				if entry, isEntry := sx.GetPair(entryObj); isEntry && entry != nil {
+					lines := le.layoutBlocks(entry.Tail().Tail(), reduceWidth(width, len(indent)), false)
+					result = append(result, prefixLines(lines, indent, indent)...)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (le *LayoutEncoder) layoutRegion(node *sx.Pair, width int, prefix string) []string {
+	// (REGION-BLOCK attrs (block...) inline...)
+	next := node.Tail().Tail()
+	lines := le.layoutBlocks(next.Head(), reduceWidth(width, len(prefix)), false)
+	if cite := le.inlineString(next.Tail()); cite != "" {
+		lines = append(lines, wrapText("-- "+cite, reduceWidth(width, len(prefix)))...)
+	}
+	return prefixLines(lines, prefix, prefix)
+}
+
+func layoutVerbatim(node *sx.Pair) []string {
+	const indent = "    "
+	_, _, content := zsx.GetLiteral(node)
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	return prefixLines(lines, indent, indent)
+}
+
+type tableCell struct {
+	text  string
+	align byte
+}
+
+func (le *LayoutEncoder) layoutTable(node *sx.Pair) []string {
+	// (TABLE attrs header-row row...)
+	next := node.Tail().Tail()
+	header := le.tableRow(next.Head())
+	var rows [][]tableCell
+	for obj := range next.Tail().Values() {
+		if row, isPair := sx.GetPair(obj); isPair && row != nil {
+			rows = append(rows, le.tableRow(row))
+		}
+	}
+
+	var widths []int
+	for _, row := range append([][]tableCell{header}, rows...) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell.text))
+		}
+	}
+	if len(widths) == 0 {
+		return nil
+	}
+
+	border := tableBorder(widths, '-')
+	result := []string{border}
+	if len(header) > 0 {
+		result = append(result, tableLine(header, widths), tableBorder(widths, '='))
+	}
+	for _, row := range rows {
+		result = append(result, tableLine(row, widths))
+	}
+	return append(result, border)
+}
+
+func (le *LayoutEncoder) tableRow(row *sx.Pair) []tableCell {
+	if row == nil {
+		return nil
+	}
+	_, cells := zsx.GetRow(row)
+	var result []tableCell
+	for obj := range cells.Values() {
+		cell, isPair := sx.GetPair(obj)
+		if !isPair || cell == nil {
+			continue
+		}
+		// (CELL attrs inline...)
+		attrs := cell.Tail().Head()
+		tc := tableCell{text: strings.ReplaceAll(le.inlineString(cell.Tail().Tail()), "\n", " ")}
+		if alignPair := attrs.Assoc(zsx.SymAttrAlign); alignPair != nil {
+			if align, isString := sx.GetString(alignPair.Cdr()); isString {
+				switch align.GetValue() {
+				case zsx.AttrAlignCenter.GetValue():
+					tc.align = ':'
+				case zsx.AttrAlignRight.GetValue():
+					tc.align = '>'
+				}
+			}
+		}
+		result = append(result, tc)
+	}
+	return result
+}
+
+func tableBorder(widths []int, ch byte) string {
+	var sb strings.Builder
+	sb.WriteByte('+')
+	for _, w := range widths {
+		sb.WriteString(strings.Repeat(string(ch), w+2))
+		sb.WriteByte('+')
+	}
+	return sb.String()
+}
+
+func tableLine(row []tableCell, widths []int) string {
+	var sb strings.Builder
+	sb.WriteByte('|')
+	for i, w := range widths {
+		var cell tableCell
+		if i < len(row) {
+			cell = row[i]
+		}
+		fill := w - utf8.RuneCountInString(cell.text)
+		left := 0
+		switch cell.align {
+		case ':':
+			left = fill / 2
+		case '>':
+			left = fill
+		}
+		sb.WriteByte(' ')
+		sb.WriteString(strings.Repeat(" ", left))
+		sb.WriteString(cell.text)
+		sb.WriteString(strings.Repeat(" ", fill-left))
+		sb.WriteString(" |")
+	}
+	return sb.String()
+}
+
+// inlineString returns the text of the given inline list. In contrast to
+// [Encoder], endnotes are numbered and link targets are retained.
+func (le *LayoutEncoder) inlineString(lst *sx.Pair) string {
+	var sb strings.Builder
+	le.writeInlines(&sb, lst)
+	return sb.String()
+}
+
+func (le *LayoutEncoder) writeInlines(sb *strings.Builder, lst *sx.Pair) {
+	for obj := range lst.Values() {
+		if node, isPair := sx.GetPair(obj); isPair && node != nil {
+			le.writeInline(sb, node)
+		}
+	}
+}
+
+func (le *LayoutEncoder) writeInline(sb *strings.Builder, node *sx.Pair) {
+	switch sym := zsx.NodeSymbol(node); sym {
+	case zsx.SymText:
+		if s, isString := sx.GetString(node.Tail().Car()); isString {
+			sb.WriteString(s.GetValue())
+		}
+	case zsx.SymSoft:
+		sb.WriteByte(' ')
+	case zsx.SymHard:
+		sb.WriteByte('\n')
+	case zsx.SymLink:
+		// (LINK attrs reference inline...)
+		ref := node.Tail().Tail()
+		refSym, refValue := zsx.GetReference(ref.Head())
+		if text := ref.Tail(); text != nil {
+			le.writeInlines(sb, text)
+			if refSym == zsx.SymRefStateExternal {
+				sb.WriteString(" <")
+				sb.WriteString(refValue)
+				sb.WriteByte('>')
+			}
+		} else {
+			sb.WriteString(refValue)
+		}
+	case zsx.SymEmbed:
+		// (EMBED attrs reference syntax inline...)
+		le.writeInlines(sb, node.Tail().Tail().Tail().Tail())
+	case zsx.SymEmbedBLOB:
+		// (EMBED-BLOB attrs syntax data inline...)
+		le.writeInlines(sb, node.Tail().Tail().Tail().Tail())
+	case zsx.SymCite:
+		// (CITE attrs key inline...)
+		next := node.Tail().Tail()
+		if key, isString := sx.GetString(next.Car()); isString {
+			sb.WriteString(key.GetValue())
+		}
+		if text := next.Tail(); text != nil {
+			sb.WriteString(", ")
+			le.writeInlines(sb, text)
+		}
+	case zsx.SymMark:
+		// (MARK attrs mark inline...)
+		le.writeInlines(sb, node.Tail().Tail().Tail())
+	case zsx.SymEndnote:
+		le.endnotes = append(le.endnotes, node.Tail().Tail())
+		sb.WriteByte('[')
+		sb.WriteString(strconv.Itoa(len(le.endnotes)))
+		sb.WriteByte(']')
+	case zsx.SymFormatQuote:
+		sb.WriteByte('"')
+		le.writeInlines(sb, node.Tail().Tail())
+		sb.WriteByte('"')
+	case zsx.SymFormatDelete, zsx.SymFormatEmph, zsx.SymFormatInsert, zsx.SymFormatMark,
+		zsx.SymFormatSpan, zsx.SymFormatStrong, zsx.SymFormatSub, zsx.SymFormatSuper:
+		le.writeInlines(sb, node.Tail().Tail())
+	case zsx.SymLiteralCode, zsx.SymLiteralInput, zsx.SymLiteralMath, zsx.SymLiteralOutput:
+		_, _, s := zsx.GetLiteral(node)
+		sb.WriteString(s)
+	case zsx.SymInline:
+		le.writeInlines(sb, node.Tail())
+	}
+}
+
+// wrapText splits the given text into lines that are not longer than width,
+// if possible. Explicit line breaks are retained.
+func wrapText(s string, width int) []string {
+	var result []string
+	for para := range strings.SplitSeq(s, "\n") {
+		words := strings.FieldsFunc(para, func(r rune) bool { return r == ' ' || r == '\t' })
+		if len(words) == 0 {
+			if len(result) > 0 {
+				result = append(result, "")
+			}
+			continue
+		}
+		var line strings.Builder
+		lineLen := 0
+		for _, word := range words {
+			wordLen := utf8.RuneCountInString(word)
+			if lineLen > 0 {
+				if width > 0 && lineLen+1+wordLen > width {
+					result = append(result, line.String())
+					line.Reset()
+					lineLen = 0
+				} else {
+					line.WriteByte(' ')
+					lineLen++
+				}
+			}
+			line.WriteString(word)
+			lineLen += wordLen
+		}
+		result = append(result, line.String())
+	}
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+func reduceWidth(width, indent int) int {
+	if width <= 0 {
+		return width
+	}
+	return max(width-indent, 1)
+}
+
+func prefixLines(lines []string, first, rest string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		result[i] = prefix + line
+	}
+	return result
+}
@@ -48,3 +48,56 @@ func TestSzText(t *testing.T) {
 		}
 	}
 }
+
+func TestLayoutEncoder(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		src   string
+		width int
+		exp   string
+	}{
+		{"()", 0, ""},
+		{`(BLOCK (PARA (TEXT "a b c d e")))`, 3, "a b\nc d\ne\n"},
+		{`(BLOCK (PARA (TEXT "a") (HARD) (TEXT "b")))`, 0, "a\nb\n"},
+		{`(BLOCK (PARA (TEXT "a")) (PARA (TEXT "b")))`, 0, "a\n\nb\n"},
+		{`(BLOCK (HEADING () 1 (TEXT "Title")) (HEADING () 2 (TEXT "Sub")))`, 0, "Title\n=====\n\nSub\n---\n"},
+		{`(BLOCK (THEMATIC ()))`, 5, "-----\n"},
+		{
+			`(BLOCK (UNORDERED () (ITEM () (PARA (TEXT "a"))) (ITEM () (PARA (TEXT "b")) (ORDERED () (ITEM () (PARA (TEXT "c d")))))))`,
+			6, "* a\n* b\n  1. c\n     d\n",
+		},
+		{`(BLOCK (QUOTATION () (ITEM () (PARA (TEXT "a b")))))`, 4, "> a\n> b\n"},
+		{
+			`(BLOCK (DESCRIPTION () (TERM () (TEXT "t")) (DETAIL (ENTRY () (PARA (TEXT "d"))))))`,
+			0, "t\n    d\n",
+		},
+		{
+			`(BLOCK (TABLE () (ROW () (CELL () (TEXT "h1")) (CELL ((align . "right")) (TEXT "h2"))) (ROW () (CELL () (TEXT "a")) (CELL ((align . "right")) (TEXT "b"))) (ROW () (CELL ((align . "center")) (TEXT "c")) (CELL ((align . "right")) (TEXT "ddd")))))`,
+			0, "+----+-----+\n| h1 |  h2 |\n+====+=====+\n| a  |   b |\n| c  | ddd |\n+----+-----+\n",
+		},
+		{`(BLOCK (TABLE () () (ROW () (CELL () (TEXT "a")))))`, 0, "+---+\n| a |\n+---+\n"},
+		{`(BLOCK (VERBATIM-CODE () "x := 1\ny := 2"))`, 0, "    x := 1\n    y := 2\n"},
+		{
+			`(BLOCK (PARA (TEXT "a") (ENDNOTE () (TEXT "n1")) (TEXT " b") (ENDNOTE () (TEXT "n2"))))`,
+			0, "a[1] b[2]\n\n[1] n1\n[2] n2\n",
+		},
+		{`(BLOCK (PARA (LINK () (EXTERNAL "https://z.de") (TEXT "Z")) (SOFT) (LINK () (HOSTED "/a"))))`, 0, "Z <https://z.de> /a\n"},
+		{`(INLINE (FORMAT-QUOTE () (TEXT "q")) (LITERAL-CODE () "c"))`, 0, "\"q\"c\n"},
+	}
+	for i, tc := range testcases {
+		sval, err := sxreader.MakeReader(strings.NewReader(tc.src)).Read()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		node, isPair := sx.GetPair(sval)
+		if !isPair {
+			t.Errorf("%d: not a list: %v", i, sval)
+			continue
+		}
+		got := text.NewLayoutEncoder(tc.width).Encode(node)
+		if got != tc.exp {
+			t.Errorf("%d: Encode(%q, %d)\nexpected %q,\n but got %q", i, tc.src, tc.width, tc.exp, got)
+		}
+	}
+}